@@ -0,0 +1,93 @@
+// Command wecom-preview 启动本地预览服务，将目录中的模板卡片和被动回复JSON文件渲染为画廊页面。
+//
+// 用法：
+//
+//	wecom-preview -dir ./cards -addr :8080
+//
+// JSON文件可以是 TemplateCard（含card_type字段）或 PassiveReply（含msgtype或response_type字段），
+// 每次刷新页面都会重新读取文件。
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/go-sphere/wecom-bot-api/wecomapi"
+	"github.com/go-sphere/wecom-bot-api/wecompreview"
+)
+
+func main() {
+	dir := flag.String("dir", ".", "directory containing card and reply JSON files")
+	addr := flag.String("addr", "localhost:8080", "listen address")
+	flag.Parse()
+
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		items, err := loadItems(*dir)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		var buf bytes.Buffer
+		if err := wecompreview.RenderPage(&buf, "WeCom Preview · "+*dir, items); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = buf.WriteTo(w)
+	})
+	log.Printf("serving previews of %s on http://%s", *dir, *addr)
+	log.Fatal(http.ListenAndServe(*addr, nil))
+}
+
+func loadItems(dir string) ([]wecompreview.Item, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	items := make([]wecompreview.Item, 0, len(files))
+	for _, file := range files {
+		items = append(items, loadItem(file))
+	}
+	return items, nil
+}
+
+func loadItem(file string) wecompreview.Item {
+	item := wecompreview.Item{Name: filepath.Base(file)}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		item.Err = err
+		return item
+	}
+	var probe struct {
+		CardType     string `json:"card_type"`
+		MsgType      string `json:"msgtype"`
+		ResponseType string `json:"response_type"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		item.Err = err
+		return item
+	}
+	if probe.CardType != "" {
+		var card wecomapi.TemplateCard
+		if item.Err = json.Unmarshal(data, &card); item.Err == nil {
+			item.Card = &card
+			item.Err = card.Validate()
+		}
+		return item
+	}
+	var reply wecomapi.PassiveReply
+	if item.Err = json.Unmarshal(data, &reply); item.Err == nil {
+		item.Reply = &reply
+		if reply.TemplateCard != nil && reply.TemplateCard.CardType != "" {
+			item.Err = reply.TemplateCard.Validate()
+		}
+	}
+	return item
+}
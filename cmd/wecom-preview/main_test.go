@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/wecom-bot-api/wecompreview"
+)
+
+func TestLoadItems(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"a_card.json":    `{"card_type":"text_notice","main_title":{"title":"<通知>"},"card_action":{"type":1,"url":"https://example.com"}}`,
+		"b_invalid.json": `{"card_type":"button_interaction"}`,
+		"c_reply.json":   `{"msgtype":"markdown","markdown":{"content":"[x](javascript:alert(1))"}}`,
+		"d_broken.json":  `{`,
+		"e_notes.txt":    `ignored`,
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	items, err := loadItems(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 4 {
+		t.Fatalf("loaded %d items, want 4", len(items))
+	}
+	tests := []struct {
+		name    string
+		card    bool
+		reply   bool
+		wantErr bool
+	}{
+		{"a_card.json", true, false, false},
+		{"b_invalid.json", true, false, true},
+		{"c_reply.json", false, true, false},
+		{"d_broken.json", false, false, true},
+	}
+	for i, tt := range tests {
+		item := items[i]
+		if item.Name != tt.name || (item.Card != nil) != tt.card || (item.Reply != nil) != tt.reply || (item.Err != nil) != tt.wantErr {
+			t.Errorf("items[%d] = %+v, want %+v", i, item, tt)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := wecompreview.RenderPage(&buf, "preview", items); err != nil {
+		t.Fatal(err)
+	}
+	page := buf.String()
+	for _, want := range []string{"&lt;通知&gt;", `<pre class="error">`, "[x](javascript:alert(1))"} {
+		if !strings.Contains(page, want) {
+			t.Errorf("page lacks %q", want)
+		}
+	}
+	if strings.Contains(page, `href="javascript:`) {
+		t.Error("page contains a javascript link")
+	}
+}
@@ -0,0 +1,98 @@
+package wecomapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// APIError 企业微信接口返回的错误。
+type APIError struct {
+	ErrCode int    `json:"errcode"`
+	ErrMsg  string `json:"errmsg"`
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("wecomapi: errcode %d: %s", e.ErrCode, e.ErrMsg)
+}
+
+// ActiveReplyClient 通过回调中的 response_url 主动回复消息。
+// 每个 response_url 只能调用一次，有效期为1小时。
+type ActiveReplyClient struct {
+	httpClient *http.Client
+	check      bool
+}
+
+// ActiveReplyOption 配置 ActiveReplyClient。
+type ActiveReplyOption func(*ActiveReplyClient)
+
+// WithActiveReplyCheck 在 SendFor 发送前调用 CheckActiveReply 检查回复与原始回调是否匹配，
+// 不合法的回复不会发送，用于开发调试。
+func WithActiveReplyCheck() ActiveReplyOption {
+	return func(c *ActiveReplyClient) {
+		c.check = true
+	}
+}
+
+// NewActiveReplyClient 创建主动回复客户端，httpClient为nil时使用 http.DefaultClient。
+func NewActiveReplyClient(httpClient *http.Client, opts ...ActiveReplyOption) *ActiveReplyClient {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	c := &ActiveReplyClient{httpClient: httpClient}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// SendFor 向回调的 response_url 发送主动回复，启用 WithActiveReplyCheck 时先检查回复是否合法。
+func (c *ActiveReplyClient) SendFor(ctx context.Context, cb *Callback, reply *PassiveReply) error {
+	if cb.ResponseURL == "" {
+		return errors.New("wecomapi: active reply: callback has no response_url")
+	}
+	if c.check {
+		if err := CheckActiveReply(cb, reply); err != nil {
+			return err
+		}
+	}
+	return c.Send(ctx, cb.ResponseURL, reply)
+}
+
+// Send 向 response_url 发送主动回复，支持 markdown 和 template_card 消息，
+// 其中 template_card 仅支持单聊。
+func (c *ActiveReplyClient) Send(ctx context.Context, responseURL string, reply *PassiveReply) error {
+	body, err := json.Marshal(reply)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, responseURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("wecomapi: active reply: unexpected status %s", resp.Status)
+	}
+	var apiErr APIError
+	if err := json.Unmarshal(data, &apiErr); err != nil {
+		return fmt.Errorf("wecomapi: active reply: %w", err)
+	}
+	if apiErr.ErrCode != 0 {
+		return &apiErr
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package wecomapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestActiveReplySendForCheck(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}))
+	defer srv.Close()
+
+	card := NewTemplateCardReply(&TemplateCard{CardType: TemplateCardTypeTextNotice})
+	group := &Callback{ChatType: ChatTypeGroup, ResponseURL: srv.URL}
+	single := &Callback{ChatType: ChatTypeSingle, ResponseURL: srv.URL}
+
+	checked := NewActiveReplyClient(srv.Client(), WithActiveReplyCheck())
+	err := checked.SendFor(context.Background(), group, card)
+	var replyErr *ReplyError
+	if !errors.As(err, &replyErr) || !errors.Is(err, ErrIllegalReply) {
+		t.Fatalf("template_card to group = %v, want ReplyError", err)
+	}
+	if n := requests.Load(); n != 0 {
+		t.Fatalf("illegal reply was sent (%d requests)", n)
+	}
+	if err := checked.SendFor(context.Background(), single, card); err != nil {
+		t.Fatalf("template_card to single: %v", err)
+	}
+	if err := checked.SendFor(context.Background(), group, NewMarkdownReply("hi")); err != nil {
+		t.Fatalf("markdown to group: %v", err)
+	}
+
+	unchecked := NewActiveReplyClient(srv.Client())
+	if err := unchecked.SendFor(context.Background(), group, card); err != nil {
+		t.Fatalf("unchecked send: %v", err)
+	}
+	if n := requests.Load(); n != 3 {
+		t.Errorf("requests = %d, want 3", n)
+	}
+
+	if err := checked.SendFor(context.Background(), &Callback{}, NewMarkdownReply("hi")); err == nil {
+		t.Error("callback without response_url was accepted")
+	}
+}
+
+func TestActiveReplyAPIError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"errcode":40001,"errmsg":"invalid"}`))
+	}))
+	defer srv.Close()
+
+	err := NewActiveReplyClient(srv.Client()).Send(context.Background(), srv.URL, NewMarkdownReply("hi"))
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.ErrCode != 40001 {
+		t.Fatalf("Send = %v, want APIError 40001", err)
+	}
+}
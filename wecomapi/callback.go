@@ -1,6 +1,10 @@
 package wecomapi
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+)
 
 // ChatType 回调会话类型。
 type ChatType string
@@ -23,6 +27,16 @@ const (
 	CallbackMsgTypeEvent  CallbackMsgType = "event"
 )
 
+// IsKnown 判断是否为SDK已支持的消息类型。
+func (t CallbackMsgType) IsKnown() bool {
+	switch t {
+	case CallbackMsgTypeText, CallbackMsgTypeImage, CallbackMsgTypeMixed, CallbackMsgTypeVoice,
+		CallbackMsgTypeFile, CallbackMsgTypeStream, CallbackMsgTypeEvent:
+		return true
+	}
+	return false
+}
+
 // MsgItemType 混排消息项类型。
 type MsgItemType string
 
@@ -87,7 +101,7 @@ type Callback struct {
 	CreateTime  int64           `json:"create_time,omitempty"`
 	AIBotID     string          `json:"aibotid"`
 	ChatID      string          `json:"chatid,omitempty"`
-	ChatType    ChatType        `json:"chattype"` // single 或 group
+	ChatType    ChatType        `json:"chattype,omitempty"` // single 或 group（进入会话事件不返回）
 	From        From            `json:"from"`
 	ResponseURL string          `json:"response_url,omitempty"`
 	MsgType     CallbackMsgType `json:"msgtype"` // text/image/mixed/voice/file/stream/event
@@ -99,6 +113,57 @@ type Callback struct {
 	Stream      *Stream         `json:"stream,omitempty"`
 	Quote       *Quote          `json:"quote,omitempty"`
 	Event       *Event          `json:"event,omitempty"`
+	Unknown     json.RawMessage `json:"-"` // 未知消息类型的原始内容（与msgtype同名的字段）
+
+	raw *rawObject // 原始JSON，用于按需解析未知字段
+}
+
+var callbackFields = sync.OnceValue(func() map[string]struct{} {
+	return jsonFieldNames(reflect.TypeFor[Callback]())
+})
+
+// UnmarshalJSON 自定义反序列化，保留原始JSON以便按需解析。
+func (c *Callback) UnmarshalJSON(data []byte) error {
+	type Alias Callback
+	if err := json.Unmarshal(data, (*Alias)(c)); err != nil {
+		return err
+	}
+	c.raw = newRawObject(data)
+	c.Unknown = nil
+	if c.MsgType != "" && !c.MsgType.IsKnown() {
+		c.Unknown, _ = c.Field(string(c.MsgType))
+	}
+	return nil
+}
+
+// IsKnownType 判断回调的消息类型是否为SDK已支持的类型。
+func (c *Callback) IsKnownType() bool {
+	return c.MsgType.IsKnown()
+}
+
+// MarshalJSON 自定义序列化，保留反序列化时的未知字段。
+func (c Callback) MarshalJSON() ([]byte, error) {
+	type Alias Callback
+	data, err := json.Marshal(Alias(c))
+	if err != nil {
+		return nil, err
+	}
+	return appendExtraFields(data, c.Extra())
+}
+
+// Raw 返回回调的原始JSON，未经反序列化时为nil。
+func (c *Callback) Raw() json.RawMessage {
+	return c.raw.raw()
+}
+
+// Field 返回原始JSON中指定字段的值。
+func (c *Callback) Field(name string) (json.RawMessage, bool) {
+	return c.raw.field(name)
+}
+
+// Extra 返回原始JSON中未映射到结构体的字段。
+func (c *Callback) Extra() map[string]json.RawMessage {
+	return c.raw.extra(callbackFields())
 }
 
 // From 消息发送者信息。
@@ -160,30 +225,56 @@ type Event struct {
 	EnterChat         *EnterChatEvent    `json:"enter_chat,omitempty"`          // 进入会话事件
 	TemplateCardEvent *TemplateCardEvent `json:"template_card_event,omitempty"` // 模板卡片事件
 	FeedbackEvent     *FeedbackEvent     `json:"feedback_event,omitempty"`      // 用户反馈事件
-	RawData           map[string]any     `json:"-"`                             // 原始数据，用于自定义事件解析
+
+	// Deprecated: 使用 Field、Extra 或 DecodeEvent。仅在自定义事件（非SDK内置的事件类型）中填充。
+	RawData map[string]any `json:"-"`
+
+	raw *rawObject // 原始JSON，用于自定义事件的按需解析
 }
 
-// UnmarshalJSON 自定义反序列化，保存原始数据。
+var eventFields = sync.OnceValue(func() map[string]struct{} {
+	return jsonFieldNames(reflect.TypeFor[Event]())
+})
+
+// UnmarshalJSON 自定义反序列化，保留原始JSON以便按需解析。
 func (e *Event) UnmarshalJSON(data []byte) error {
 	type Alias Event
-	aux := &struct {
-		*Alias
-	}{
-		Alias: (*Alias)(e),
-	}
-
-	if err := json.Unmarshal(data, aux); err != nil {
+	if err := json.Unmarshal(data, (*Alias)(e)); err != nil {
 		return err
 	}
+	e.raw = newRawObject(data)
+	e.RawData = nil
+	switch e.EventType {
+	case EventTypeEnterChat, EventTypeTemplateCard, EventTypeFeedback:
+		return nil
+	}
+	// 兼容旧版：自定义事件仍提供整体解析的RawData
+	return json.Unmarshal(data, &e.RawData)
+}
 
-	// 保存原始数据，便于自定义解析
-	var raw map[string]any
-	if err := json.Unmarshal(data, &raw); err != nil {
-		return err
+// MarshalJSON 自定义序列化，保留反序列化时的未知字段。
+func (e Event) MarshalJSON() ([]byte, error) {
+	type Alias Event
+	data, err := json.Marshal(Alias(e))
+	if err != nil {
+		return nil, err
 	}
-	e.RawData = raw
+	return appendExtraFields(data, e.Extra())
+}
 
-	return nil
+// Raw 返回事件的原始JSON，未经反序列化时为nil。
+func (e *Event) Raw() json.RawMessage {
+	return e.raw.raw()
+}
+
+// Field 返回原始JSON中指定字段的值。
+func (e *Event) Field(name string) (json.RawMessage, bool) {
+	return e.raw.field(name)
+}
+
+// Extra 返回原始JSON中未映射到结构体的字段，如自定义事件的数据。
+func (e *Event) Extra() map[string]json.RawMessage {
+	return e.raw.extra(eventFields())
 }
 
 // EnterChatEvent 进入会话事件。
@@ -197,6 +288,18 @@ type TemplateCardEvent struct {
 	SelectedItems *SelectedItems   `json:"selected_items,omitempty"` // 用户提交的选择框数据
 }
 
+// Selections 返回按选择题key分组的选中选项ID。
+func (e *TemplateCardEvent) Selections() map[string][]string {
+	if e.SelectedItems == nil {
+		return nil
+	}
+	selections := make(map[string][]string, len(e.SelectedItems.SelectedItem))
+	for _, item := range e.SelectedItems.SelectedItem {
+		selections[item.QuestionKey] = item.OptionIDs.OptionID
+	}
+	return selections
+}
+
 // SelectedItems 选中项列表。
 type SelectedItems struct {
 	SelectedItem []SelectedItem `json:"selected_item"` // 选中项列表
@@ -0,0 +1,206 @@
+package wecomapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const sampleCallback = `{
+	"msgid": "CAIQ16HMjQYY/NGagIOAgAMgq4KM0AI=",
+	"create_time": 1700000000,
+	"aibotid": "AIBOTID",
+	"chatid": "CHATID",
+	"chattype": "group",
+	"from": {"corpid": "CORPID", "userid": "USERID"},
+	"response_url": "https://example.com/response",
+	"msgtype": "event",
+	"trace": {"id": "t-1", "hops": [1, 2, 3]},
+	"event": {
+		"eventtype": "template_card_event",
+		"template_card_event": {
+			"card_type": "vote_interaction",
+			"event_key": "button_key",
+			"task_id": "TASKID",
+			"selected_items": {
+				"selected_item": [
+					{"question_key": "q1", "option_ids": {"option_id": ["a", "b"]}}
+				]
+			}
+		},
+		"custom": {"level": 3}
+	}
+}`
+
+// legacyEvent 模拟旧版事件解析：结构体反序列化后再整体解析为map。
+type legacyEvent struct {
+	EventType         EventType          `json:"eventtype"`
+	EnterChat         *EnterChatEvent    `json:"enter_chat,omitempty"`
+	TemplateCardEvent *TemplateCardEvent `json:"template_card_event,omitempty"`
+	FeedbackEvent     *FeedbackEvent     `json:"feedback_event,omitempty"`
+	RawData           map[string]any     `json:"-"`
+}
+
+func (e *legacyEvent) UnmarshalJSON(data []byte) error {
+	type Alias legacyEvent
+	if err := json.Unmarshal(data, (*Alias)(e)); err != nil {
+		return err
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	e.RawData = raw
+	return nil
+}
+
+type legacyCallback struct {
+	MsgID       string          `json:"msgid"`
+	CreateTime  int64           `json:"create_time,omitempty"`
+	AIBotID     string          `json:"aibotid"`
+	ChatID      string          `json:"chatid,omitempty"`
+	ChatType    ChatType        `json:"chattype"`
+	From        From            `json:"from"`
+	ResponseURL string          `json:"response_url,omitempty"`
+	MsgType     CallbackMsgType `json:"msgtype"`
+	Text        *Text           `json:"text,omitempty"`
+	Event       *legacyEvent    `json:"event,omitempty"`
+}
+
+func BenchmarkCallbackUnmarshal(b *testing.B) {
+	data := []byte(sampleCallback)
+	b.Run("raw", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			var cb Callback
+			if err := json.Unmarshal(data, &cb); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("legacy-map", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			var cb legacyCallback
+			if err := json.Unmarshal(data, &cb); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestCallbackRoundTrip(t *testing.T) {
+	var cb Callback
+	if err := json.Unmarshal([]byte(sampleCallback), &cb); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cb.Extra()["trace"]; !ok {
+		t.Errorf("callback extra missing trace: %v", cb.Extra())
+	}
+	if _, ok := cb.Event.Extra()["custom"]; !ok {
+		t.Errorf("event extra missing custom: %v", cb.Event.Extra())
+	}
+
+	out, err := json.Marshal(cb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want, got map[string]any
+	if err := json.Unmarshal([]byte(sampleCallback), &want); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("marshal produced invalid JSON: %v\n%s", err, out)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip mismatch\n got: %s\nwant: %s", out, sampleCallback)
+	}
+
+	// 再次往返应保持稳定。
+	var again Callback
+	if err := json.Unmarshal(out, &again); err != nil {
+		t.Fatal(err)
+	}
+	out2, err := json.Marshal(again)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out2) != string(out) {
+		t.Errorf("second round trip differs\nfirst:  %s\nsecond: %s", out, out2)
+	}
+}
+
+func TestCallbackUnknownMsgType(t *testing.T) {
+	data := `{"msgid":"1","aibotid":"B","chattype":"single","from":{"userid":"U"},"msgtype":"location","location":{"lat":1.5}}`
+	var cb Callback
+	if err := json.Unmarshal([]byte(data), &cb); err != nil {
+		t.Fatal(err)
+	}
+	if cb.IsKnownType() {
+		t.Fatal("location reported as known type")
+	}
+	if string(cb.Unknown) != `{"lat":1.5}` {
+		t.Errorf("Unknown = %s", cb.Unknown)
+	}
+	out, err := json.Marshal(cb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want, got map[string]any
+	_ = json.Unmarshal([]byte(data), &want)
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip mismatch\n got: %s\nwant: %s", out, data)
+	}
+}
+
+func TestCallbackRawBufferReuse(t *testing.T) {
+	buf := []byte(`{"msgid":"1","msgtype":"text","text":{"content":"first"},"trace":1}`)
+	var cb Callback
+	if err := json.Unmarshal(buf, &cb); err != nil {
+		t.Fatal(err)
+	}
+	first := cb.Raw()
+	extra := cb.Extra()
+	copy(buf, bytes.Repeat([]byte(" "), len(buf)))
+	if !json.Valid(first) || string(first[2:7]) != "msgid" {
+		t.Errorf("Raw aliases the input buffer: %q", first)
+	}
+
+	extra["trace"] = json.RawMessage(`2`)
+	if v, _ := cb.Field("trace"); string(v) != "1" {
+		t.Errorf("modifying Extra changed Field: %s", v)
+	}
+
+	if err := json.Unmarshal([]byte(`{"msgid":"2","msgtype":"text","text":{"content":"second"}}`), &cb); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cb.Field("trace"); ok {
+		t.Error("Field returned data from the previous document")
+	}
+	if string(first[2:7]) != "msgid" || !bytes.Contains(first, []byte("first")) {
+		t.Errorf("earlier Raw was overwritten: %q", first)
+	}
+}
+
+func TestEventRawData(t *testing.T) {
+	var custom Event
+	if err := json.Unmarshal([]byte(`{"eventtype":"custom_event","custom_event":{"n":1}}`), &custom); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{"eventtype": "custom_event", "custom_event": map[string]any{"n": float64(1)}}
+	if !reflect.DeepEqual(custom.RawData, want) {
+		t.Errorf("RawData = %v, want %v", custom.RawData, want)
+	}
+
+	var builtin Event
+	if err := json.Unmarshal([]byte(`{"eventtype":"feedback_event","feedback_event":{"id":"f"}}`), &builtin); err != nil {
+		t.Fatal(err)
+	}
+	if builtin.RawData != nil {
+		t.Errorf("RawData filled for builtin event: %v", builtin.RawData)
+	}
+}
@@ -0,0 +1,120 @@
+package wecomapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var (
+	// ErrEventTypeMismatch 事件数据结构与注册的类型不一致。
+	ErrEventTypeMismatch = errors.New("wecomapi: event payload type mismatch")
+	// ErrNoEventPayload 事件中缺少与eventtype同名的数据字段。
+	ErrNoEventPayload = errors.New("wecomapi: event payload missing")
+)
+
+var eventRegistry = struct {
+	sync.RWMutex
+	types map[EventType]reflect.Type
+}{
+	types: map[EventType]reflect.Type{
+		EventTypeEnterChat:    reflect.TypeFor[EnterChatEvent](),
+		EventTypeTemplateCard: reflect.TypeFor[TemplateCardEvent](),
+		EventTypeFeedback:     reflect.TypeFor[FeedbackEvent](),
+	},
+}
+
+// RegisterEvent 注册事件类型对应的数据结构。
+// 事件数据位于event中与eventtype同名的字段。
+// 同一事件类型已注册为不同结构时panic，应在初始化阶段调用。
+func RegisterEvent[T any](eventType EventType) {
+	t := reflect.TypeFor[T]()
+	eventRegistry.Lock()
+	defer eventRegistry.Unlock()
+	if old, ok := eventRegistry.types[eventType]; ok && old != t {
+		panic(fmt.Sprintf("wecomapi: event type %q already registered as %s", eventType, old))
+	}
+	eventRegistry.types[eventType] = t
+}
+
+// DecodeEvent 将事件数据解码为注册的结构。事件中缺少数据字段时返回 ErrNoEventPayload，
+// 没有字段的结构（如 EnterChatEvent）除外，此时返回零值。
+func DecodeEvent[T any](e *Event) (*T, error) {
+	if e == nil {
+		return nil, errors.New("wecomapi: nil event")
+	}
+	if err := checkEventType(e.EventType, reflect.TypeFor[T]()); err != nil {
+		return nil, err
+	}
+	if v, ok := e.builtinPayload().(*T); ok {
+		return v, nil
+	}
+	payload := new(T)
+	if err := e.decodeRaw(payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// Payload 按注册的结构解码事件数据，返回值为指向该结构的指针；缺少数据字段时的处理与 DecodeEvent 相同。
+func (e *Event) Payload() (any, error) {
+	eventRegistry.RLock()
+	t, ok := eventRegistry.types[e.EventType]
+	eventRegistry.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("wecomapi: event type %q not registered", e.EventType)
+	}
+	if p := e.builtinPayload(); p != nil && reflect.TypeOf(p).Elem() == t {
+		return p, nil
+	}
+	payload := reflect.New(t).Interface()
+	if err := e.decodeRaw(payload); err != nil {
+		return nil, err
+	}
+	return payload, nil
+}
+
+// decodeRaw 从原始数据中解码与eventtype同名的字段。
+func (e *Event) decodeRaw(v any) error {
+	data, ok := e.Field(string(e.EventType))
+	if !ok || string(data) == "null" {
+		if t := reflect.TypeOf(v).Elem(); t.Kind() == reflect.Struct && t.NumField() == 0 {
+			return nil
+		}
+		return fmt.Errorf("%w: %s", ErrNoEventPayload, e.EventType)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("wecomapi: decode %s event: %w", e.EventType, err)
+	}
+	return nil
+}
+
+func (e *Event) builtinPayload() any {
+	switch e.EventType {
+	case EventTypeEnterChat:
+		if e.EnterChat != nil {
+			return e.EnterChat
+		}
+	case EventTypeTemplateCard:
+		if e.TemplateCardEvent != nil {
+			return e.TemplateCardEvent
+		}
+	case EventTypeFeedback:
+		if e.FeedbackEvent != nil {
+			return e.FeedbackEvent
+		}
+	}
+	return nil
+}
+
+func checkEventType(eventType EventType, t reflect.Type) error {
+	eventRegistry.RLock()
+	registered, ok := eventRegistry.types[eventType]
+	eventRegistry.RUnlock()
+	if ok && registered != t {
+		return fmt.Errorf("%w: %s is registered as %s, not %s", ErrEventTypeMismatch, eventType, registered, t)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package wecomapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type approvalEvent struct {
+	ApprovalID string `json:"approval_id"`
+	Result     int    `json:"result"`
+}
+
+func decodeCallback(t *testing.T, data string) *Callback {
+	t.Helper()
+	var cb Callback
+	if err := json.Unmarshal([]byte(data), &cb); err != nil {
+		t.Fatal(err)
+	}
+	return &cb
+}
+
+func eventCallback(t *testing.T, event string) *Callback {
+	t.Helper()
+	return decodeCallback(t, `{"msgid":"1","aibotid":"B","chattype":"single","from":{"userid":"U"},"msgtype":"event","event":`+event+`}`)
+}
+
+func TestDecodeEvent(t *testing.T) {
+	RegisterEvent[approvalEvent]("test_approval")
+
+	cb := eventCallback(t, `{"eventtype":"test_approval","test_approval":{"approval_id":"a1","result":2}}`)
+	got, err := DecodeEvent[approvalEvent](cb.Event)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ApprovalID != "a1" || got.Result != 2 {
+		t.Errorf("DecodeEvent = %+v", got)
+	}
+
+	if _, err := DecodeEvent[FeedbackEvent](cb.Event); !errors.Is(err, ErrEventTypeMismatch) {
+		t.Errorf("mismatched type = %v, want ErrEventTypeMismatch", err)
+	}
+
+	missing := eventCallback(t, `{"eventtype":"test_approval"}`)
+	if v, err := DecodeEvent[approvalEvent](missing.Event); v != nil || !errors.Is(err, ErrNoEventPayload) {
+		t.Errorf("missing payload = %v, %v; want ErrNoEventPayload", v, err)
+	}
+
+	unregistered := eventCallback(t, `{"eventtype":"test_unregistered"}`)
+	if v, err := DecodeEvent[approvalEvent](unregistered.Event); v != nil || !errors.Is(err, ErrNoEventPayload) {
+		t.Errorf("unregistered without payload = %v, %v; want ErrNoEventPayload", v, err)
+	}
+
+	enter := eventCallback(t, `{"eventtype":"enter_chat"}`)
+	if v, err := DecodeEvent[EnterChatEvent](enter.Event); v == nil || err != nil {
+		t.Errorf("enter_chat = %v, %v; want empty payload", v, err)
+	}
+
+	card := eventCallback(t, `{"eventtype":"template_card_event","template_card_event":{"card_type":"button_interaction","event_key":"k","task_id":"t"}}`)
+	tc, err := DecodeEvent[TemplateCardEvent](card.Event)
+	if err != nil || tc != card.Event.TemplateCardEvent {
+		t.Errorf("builtin payload = %v, %v; want the decoded field", tc, err)
+	}
+
+	if _, err := DecodeEvent[approvalEvent](nil); err == nil {
+		t.Error("nil event accepted")
+	}
+}
+
+func TestEventPayload(t *testing.T) {
+	RegisterEvent[approvalEvent]("test_payload")
+
+	cb := eventCallback(t, `{"eventtype":"test_payload","test_payload":{"approval_id":"a2"}}`)
+	p, err := cb.Event.Payload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, ok := p.(*approvalEvent); !ok || v.ApprovalID != "a2" {
+		t.Errorf("Payload = %#v", p)
+	}
+
+	fb := eventCallback(t, `{"eventtype":"feedback_event","feedback_event":{"id":"f","type":1}}`)
+	if p, err := fb.Event.Payload(); err != nil || p != fb.Event.FeedbackEvent {
+		t.Errorf("builtin Payload = %v, %v", p, err)
+	}
+
+	if _, err := eventCallback(t, `{"eventtype":"test_not_registered"}`).Event.Payload(); err == nil {
+		t.Error("unregistered Payload returned no error")
+	}
+	if _, err := eventCallback(t, `{"eventtype":"test_payload"}`).Event.Payload(); !errors.Is(err, ErrNoEventPayload) {
+		t.Errorf("missing Payload = %v, want ErrNoEventPayload", err)
+	}
+}
+
+func TestRegisterEventConflictPanics(t *testing.T) {
+	RegisterEvent[approvalEvent]("test_conflict")
+	RegisterEvent[approvalEvent]("test_conflict") // 相同结构可重复注册
+	defer func() {
+		if recover() == nil {
+			t.Error("conflicting registration did not panic")
+		}
+	}()
+	RegisterEvent[FeedbackEvent]("test_conflict")
+}
+
+func TestRouterDispatch(t *testing.T) {
+	reply := func(s string) HandlerFunc {
+		return func(context.Context, *Callback) (*PassiveReply, error) {
+			return NewTextReply(s), nil
+		}
+	}
+	r := NewRouter()
+	r.HandleMsg(CallbackMsgTypeText, reply("text"))
+	r.HandleMsg(CallbackMsgTypeEvent, reply("any event"))
+	r.HandleEvent(EventTypeEnterChat, reply("enter"))
+	r.HandleUnknown(reply("unknown"))
+	HandleTypedEvent(r, "test_router", func(ctx context.Context, cb *Callback, p *approvalEvent) (*PassiveReply, error) {
+		return NewTextReply("typed " + p.ApprovalID), nil
+	})
+
+	tests := []struct {
+		name string
+		cb   *Callback
+		want string
+	}{
+		{"message", decodeCallback(t, `{"msgtype":"text","text":{"content":"hi"}}`), "text"},
+		{"event", eventCallback(t, `{"eventtype":"enter_chat"}`), "enter"},
+		{"event fallback to msgtype", eventCallback(t, `{"eventtype":"feedback_event"}`), "any event"},
+		{"typed event", eventCallback(t, `{"eventtype":"test_router","test_router":{"approval_id":"a3"}}`), "typed a3"},
+		{"unknown msgtype", decodeCallback(t, `{"msgtype":"location"}`), "unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := r.Dispatch(context.Background(), tt.cb)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Text.Content != tt.want {
+				t.Errorf("reply = %q, want %q", got.Text.Content, tt.want)
+			}
+		})
+	}
+
+	if _, err := r.Dispatch(context.Background(), eventCallback(t, `{"eventtype":"test_router"}`)); !errors.Is(err, ErrNoEventPayload) {
+		t.Errorf("typed event without payload = %v, want ErrNoEventPayload", err)
+	}
+
+	empty := NewRouter()
+	if _, err := empty.Dispatch(context.Background(), decodeCallback(t, `{"msgtype":"image"}`)); !errors.Is(err, ErrNoHandler) {
+		t.Errorf("no handler = %v, want ErrNoHandler", err)
+	}
+	empty.NotFound(reply("fallback"))
+	if got, err := empty.Dispatch(context.Background(), decodeCallback(t, `{"msgtype":"image"}`)); err != nil || got.Text.Content != "fallback" {
+		t.Errorf("NotFound = %v, %v", got, err)
+	}
+}
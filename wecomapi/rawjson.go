@@ -0,0 +1,106 @@
+package wecomapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// jsonFieldNames 返回结构体json标签声明的字段名集合。
+func jsonFieldNames(t reflect.Type) map[string]struct{} {
+	names := make(map[string]struct{}, t.NumField())
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = f.Name
+		}
+		names[name] = struct{}{}
+	}
+	return names
+}
+
+// rawObject 保存反序列化时的原始JSON对象，首次访问字段时解析一次。
+// 复制结构体时共享同一个rawObject，内容只读。
+type rawObject struct {
+	data   json.RawMessage
+	once   sync.Once
+	fields map[string]json.RawMessage
+}
+
+// newRawObject 复制data，避免引用调用方可能复用的缓冲区。
+func newRawObject(data []byte) *rawObject {
+	return &rawObject{data: bytes.Clone(data)}
+}
+
+func (o *rawObject) raw() json.RawMessage {
+	if o == nil {
+		return nil
+	}
+	return o.data
+}
+
+func (o *rawObject) parse() map[string]json.RawMessage {
+	if o == nil {
+		return nil
+	}
+	o.once.Do(func() {
+		_ = json.Unmarshal(o.data, &o.fields)
+	})
+	return o.fields
+}
+
+// field 返回指定字段的原始值。
+func (o *rawObject) field(name string) (json.RawMessage, bool) {
+	v, ok := o.parse()[name]
+	return v, ok
+}
+
+// extra 返回不在known里的字段，返回的map可由调用方修改。
+func (o *rawObject) extra(known map[string]struct{}) map[string]json.RawMessage {
+	var extra map[string]json.RawMessage
+	for name, v := range o.parse() {
+		if _, ok := known[name]; ok {
+			continue
+		}
+		if extra == nil {
+			extra = make(map[string]json.RawMessage)
+		}
+		extra[name] = v
+	}
+	return extra
+}
+
+// appendExtraFields 将额外字段按名称排序追加到已序列化的JSON对象末尾。
+func appendExtraFields(obj []byte, extra map[string]json.RawMessage) ([]byte, error) {
+	if len(extra) == 0 {
+		return obj, nil
+	}
+	obj = bytes.TrimRight(obj, " \n")
+	obj = obj[:len(obj)-1] // 去掉结尾的'}'
+	needComma := len(bytes.TrimSpace(obj)) > 1
+	for _, name := range slices.Sorted(maps.Keys(extra)) {
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+		if needComma {
+			obj = append(obj, ',')
+		}
+		needComma = true
+		obj = append(obj, key...)
+		obj = append(obj, ':')
+		obj = append(obj, extra[name]...)
+	}
+	return append(obj, '}'), nil
+}
@@ -1,5 +1,11 @@
 package wecomapi
 
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+)
+
 // ReplyMsgType 被动回复的消息类型。
 type ReplyMsgType string
 
@@ -31,11 +37,22 @@ type PassiveReply struct {
 
 // StreamReply 流式消息的回复体。
 type StreamReply struct {
-	ID       string    `json:"id,omitempty"`
-	Finish   bool      `json:"finish,omitempty"`
-	Content  string    `json:"content,omitempty"`
-	MsgItem  []MsgItem `json:"msg_item,omitempty"`
-	Feedback *Feedback `json:"feedback,omitempty"`
+	ID       string          `json:"id,omitempty"`
+	Finish   bool            `json:"finish,omitempty"`
+	Content  string          `json:"content,omitempty"`
+	MsgItem  []StreamMsgItem `json:"msg_item,omitempty"`
+	Feedback *Feedback       `json:"feedback,omitempty"`
+}
+
+// StreamMsgItem 流式消息的图文混排项，目前仅支持图片，且只能在 finish=true 时设置。
+//
+// 不兼容变更：StreamReply.MsgItem 原为 []MsgItem。MsgItem 是回调中的混排结构，
+// 图片只有下载URL，而流式回复要求Base64图片，原类型无法构造合法的请求。
+// 迁移时将 MsgItem{MsgType: MsgItemTypeImage, Image: &Image{...}} 改为
+// StreamMsgItem{MsgType: MsgItemTypeImage, Image: NewImageBase64(data)}。
+type StreamMsgItem struct {
+	MsgType MsgItemType  `json:"msgtype"`         // 类型：image
+	Image   *ImageBase64 `json:"image,omitempty"` // 图片内容
 }
 
 // ImageBase64 表示Base64编码图片，用于流式混排。
@@ -44,9 +61,19 @@ type ImageBase64 struct {
 	MD5    string `json:"md5"`    // 图片内容（Base64编码前）的MD5值
 }
 
+// NewImageBase64 根据图片原始内容计算Base64编码和MD5值。
+func NewImageBase64(data []byte) *ImageBase64 {
+	sum := md5.Sum(data)
+	return &ImageBase64{
+		Base64: base64.StdEncoding.EncodeToString(data),
+		MD5:    hex.EncodeToString(sum[:]),
+	}
+}
+
 // Markdown 表示Markdown消息内容。
 type Markdown struct {
-	Content string `json:"content"` // Markdown消息内容
+	Content  string    `json:"content"`            // Markdown消息内容
+	Feedback *Feedback `json:"feedback,omitempty"` // 反馈信息（仅主动回复支持）
 }
 
 // NewTextReply 创建文本被动回复。
@@ -0,0 +1,147 @@
+package wecomapi
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrIllegalReply 回复类型不适用于回调场景，企业微信会静默丢弃此类回复。
+var ErrIllegalReply = errors.New("wecomapi: illegal reply")
+
+// ReplyError 描述回复与回调场景不匹配的原因。
+type ReplyError struct {
+	Context string // 回调场景，如 "event enter_chat"
+	Reply   string // 回复类型，如 "markdown"
+	Reason  string
+}
+
+func (e *ReplyError) Error() string {
+	return fmt.Sprintf("wecomapi: illegal reply %s for %s: %s", e.Reply, e.Context, e.Reason)
+}
+
+func (e *ReplyError) Unwrap() error { return ErrIllegalReply }
+
+// replyKind 返回回复的类型描述，nil为 "empty"。
+func replyKind(reply *PassiveReply) string {
+	switch {
+	case reply == nil:
+		return "empty"
+	case reply.ResponseType != "":
+		return string(reply.ResponseType)
+	case reply.MsgType != "":
+		return string(reply.MsgType)
+	}
+	return "unknown"
+}
+
+// callbackContext 返回回调场景的描述。
+func callbackContext(cb *Callback) string {
+	if cb.MsgType == CallbackMsgTypeEvent && cb.Event != nil {
+		return "event " + string(cb.Event.EventType)
+	}
+	return "msgtype " + string(cb.MsgType)
+}
+
+// CheckReply 检查被动回复是否适用于回调场景：
+//   - 进入会话事件只能回复文本或模板卡片；
+//   - 用户消息只能回复流式消息、模板卡片或流式消息+模板卡片；
+//   - 流式消息刷新只能回复流式消息（可附带模板卡片），且ID须与刷新的流式消息一致；
+//   - 模板卡片事件只能回复模板卡片更新，且 task_id 须与事件一致；
+//   - 用户反馈事件只能回复空包。
+//
+// 空回复除用户反馈事件外均合法。未知的回调类型不做检查。
+func CheckReply(cb *Callback, reply *PassiveReply) error {
+	fail := func(format string, args ...any) error {
+		return &ReplyError{Context: callbackContext(cb), Reply: replyKind(reply), Reason: fmt.Sprintf(format, args...)}
+	}
+	if cb.MsgType == CallbackMsgTypeEvent && cb.Event != nil && cb.Event.EventType == EventTypeFeedback {
+		if reply != nil {
+			return fail("feedback events only accept an empty reply")
+		}
+		return nil
+	}
+	if reply == nil {
+		return nil
+	}
+	switch cb.MsgType {
+	case CallbackMsgTypeEvent:
+		if cb.Event == nil {
+			return nil
+		}
+		switch cb.Event.EventType {
+		case EventTypeEnterChat:
+			if reply.ResponseType != "" || (reply.MsgType != ReplyMsgTypeText && reply.MsgType != ReplyMsgTypeTemplateCard) {
+				return fail("welcome replies must be text or template_card")
+			}
+		case EventTypeTemplateCard:
+			if reply.ResponseType != ReplyResponseTypeUpdateTemplateCard {
+				return fail("template card events only accept update_template_card")
+			}
+			if ev := cb.Event.TemplateCardEvent; ev != nil && reply.TemplateCard != nil && reply.TemplateCard.TaskID != ev.TaskID {
+				return fail("task_id %q does not match event task_id %q", reply.TemplateCard.TaskID, ev.TaskID)
+			}
+		}
+	case CallbackMsgTypeStream:
+		if reply.ResponseType != "" || (reply.MsgType != ReplyMsgTypeStream && reply.MsgType != ReplyMsgTypeStreamWithTemplateCard) {
+			return fail("stream refreshes only accept stream replies")
+		}
+		if cb.Stream != nil && reply.Stream != nil && reply.Stream.ID != "" && reply.Stream.ID != cb.Stream.ID {
+			return fail("stream id %q does not match refreshed stream %q", reply.Stream.ID, cb.Stream.ID)
+		}
+	case CallbackMsgTypeText, CallbackMsgTypeImage, CallbackMsgTypeMixed, CallbackMsgTypeVoice, CallbackMsgTypeFile:
+		switch {
+		case reply.ResponseType != "":
+			return fail("message callbacks cannot update template cards")
+		case reply.MsgType == ReplyMsgTypeStream || reply.MsgType == ReplyMsgTypeStreamWithTemplateCard:
+			if reply.Stream == nil || reply.Stream.ID == "" {
+				return fail("the first stream reply must set stream.id")
+			}
+		case reply.MsgType != ReplyMsgTypeTemplateCard:
+			return fail("message callbacks only accept stream, template_card or stream_with_template_card")
+		}
+	default:
+		return nil
+	}
+	return checkStream(reply, fail)
+}
+
+// checkStream 检查流式消息的内容长度和图片。
+func checkStream(reply *PassiveReply, fail func(string, ...any) error) error {
+	s := reply.Stream
+	if s == nil || (reply.MsgType != ReplyMsgTypeStream && reply.MsgType != ReplyMsgTypeStreamWithTemplateCard) {
+		return nil
+	}
+	if len(s.Content) > MaxStreamContentBytes {
+		return fail("stream content is %d bytes, at most %d allowed", len(s.Content), MaxStreamContentBytes)
+	}
+	if len(s.MsgItem) > 0 && !s.Finish {
+		return fail("stream images are only allowed when finish is true")
+	}
+	if len(s.MsgItem) > MaxStreamImages {
+		return fail("stream has %d images, at most %d allowed", len(s.MsgItem), MaxStreamImages)
+	}
+	return nil
+}
+
+// CheckActiveReply 检查通过回调的 response_url 发送的主动回复：
+// 只能发送Markdown或模板卡片，且模板卡片仅支持单聊。
+func CheckActiveReply(cb *Callback, reply *PassiveReply) error {
+	fail := func(format string, args ...any) error {
+		return &ReplyError{Context: "active reply to " + callbackContext(cb), Reply: replyKind(reply), Reason: fmt.Sprintf(format, args...)}
+	}
+	switch {
+	case reply == nil || reply.ResponseType != "":
+		return fail("active replies must be markdown or template_card")
+	case reply.MsgType == ReplyMsgTypeMarkdown:
+		if reply.Markdown == nil || len(reply.Markdown.Content) == 0 || len(reply.Markdown.Content) > MaxStreamContentBytes {
+			return fail("markdown content must be 1 to %d bytes", MaxStreamContentBytes)
+		}
+	case reply.MsgType == ReplyMsgTypeTemplateCard:
+		if cb.ChatType != ChatTypeSingle {
+			return fail("template_card active replies are only allowed in single chat")
+		}
+	default:
+		return fail("active replies must be markdown or template_card")
+	}
+	return nil
+}
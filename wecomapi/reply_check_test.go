@@ -0,0 +1,117 @@
+package wecomapi
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func eventCb(ev *Event) *Callback {
+	return &Callback{MsgType: CallbackMsgTypeEvent, Event: ev}
+}
+
+func TestCheckReply(t *testing.T) {
+	card := &TemplateCard{CardType: TemplateCardTypeTextNotice, TaskID: "task_1"}
+	otherCard := &TemplateCard{CardType: TemplateCardTypeTextNotice, TaskID: "task_2"}
+	stream := &StreamReply{ID: "s1", Content: "答案", Finish: true}
+	image := StreamMsgItem{MsgType: MsgItemTypeImage, Image: &ImageBase64{Base64: "x", MD5: "y"}}
+
+	enterChat := eventCb(&Event{EventType: EventTypeEnterChat})
+	cardEvent := eventCb(&Event{EventType: EventTypeTemplateCard, TemplateCardEvent: &TemplateCardEvent{TaskID: "task_1"}})
+	feedback := eventCb(&Event{EventType: EventTypeFeedback})
+	refresh := &Callback{MsgType: CallbackMsgTypeStream, Stream: &Stream{ID: "s1"}}
+	text := &Callback{MsgType: CallbackMsgTypeText}
+
+	tests := []struct {
+		name  string
+		cb    *Callback
+		reply *PassiveReply
+		ok    bool
+	}{
+		{"welcome text", enterChat, NewTextReply("你好"), true},
+		{"welcome card", enterChat, NewTemplateCardReply(card), true},
+		{"welcome empty", enterChat, nil, true},
+		{"welcome markdown", enterChat, NewMarkdownReply("你好"), false},
+		{"welcome stream", enterChat, NewStreamReply("s", "你好", true), false},
+		{"welcome card update", enterChat, NewUpdateTemplateCardReply(nil, card), false},
+
+		{"card event update", cardEvent, NewUpdateTemplateCardReply([]string{"u"}, card), true},
+		{"card event task_id mismatch", cardEvent, NewUpdateTemplateCardReply(nil, otherCard), false},
+		{"card event new card", cardEvent, NewTemplateCardReply(card), false},
+		{"card event empty", cardEvent, nil, true},
+
+		{"feedback empty", feedback, nil, true},
+		{"feedback text", feedback, NewTextReply("谢谢"), false},
+
+		{"refresh stream", refresh, NewStreamReply("s1", "答案", false), true},
+		{"refresh stream without id", refresh, NewStreamReply("", "答案", false), true},
+		{"refresh stream with card", refresh, NewStreamWithTemplateCardReply(stream, card), true},
+		{"refresh stream id mismatch", refresh, NewStreamReply("s2", "答案", false), false},
+		{"refresh stream with card id mismatch", refresh, NewStreamWithTemplateCardReply(&StreamReply{ID: "s2"}, card), false},
+		{"refresh card only", refresh, NewTemplateCardReply(card), false},
+		{"refresh text", refresh, NewTextReply("答案"), false},
+
+		{"message stream", text, NewStreamReply("s1", "答案", false), true},
+		{"message card", text, NewTemplateCardReply(card), true},
+		{"message stream with card", text, NewStreamWithTemplateCardReply(stream, card), true},
+		{"message stream without id", text, NewStreamReply("", "答案", false), false},
+		{"message markdown", text, NewMarkdownReply("答案"), false},
+		{"message text", text, NewTextReply("答案"), false},
+		{"message card update", text, NewUpdateTemplateCardReply(nil, card), false},
+
+		{"stream too long", text, NewStreamReply("s1", strings.Repeat("x", MaxStreamContentBytes+1), true), false},
+		{"stream at limit", text, NewStreamReply("s1", strings.Repeat("x", MaxStreamContentBytes), true), true},
+		{"images before finish", refresh, &PassiveReply{MsgType: ReplyMsgTypeStream, Stream: &StreamReply{ID: "s1", MsgItem: []StreamMsgItem{image}}}, false},
+		{"images on finish", refresh, &PassiveReply{MsgType: ReplyMsgTypeStream, Stream: &StreamReply{ID: "s1", Finish: true, MsgItem: []StreamMsgItem{image}}}, true},
+		{"too many images", refresh, &PassiveReply{MsgType: ReplyMsgTypeStream, Stream: &StreamReply{ID: "s1", Finish: true, MsgItem: make([]StreamMsgItem, MaxStreamImages+1)}}, false},
+
+		{"unknown callback", &Callback{MsgType: "unknown"}, NewTextReply("x"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckReply(tt.cb, tt.reply)
+			if (err == nil) != tt.ok {
+				t.Fatalf("CheckReply = %v, want ok=%v", err, tt.ok)
+			}
+			var re *ReplyError
+			if err != nil && (!errors.Is(err, ErrIllegalReply) || !errors.As(err, &re) || re.Reason == "") {
+				t.Errorf("error %v is not a *ReplyError wrapping ErrIllegalReply", err)
+			}
+		})
+	}
+}
+
+func TestCheckActiveReply(t *testing.T) {
+	single := &Callback{ChatType: ChatTypeSingle}
+	group := &Callback{ChatType: ChatTypeGroup}
+	card := &TemplateCard{CardType: TemplateCardTypeTextNotice}
+	tests := []struct {
+		name  string
+		cb    *Callback
+		reply *PassiveReply
+		ok    bool
+	}{
+		{"markdown", group, NewMarkdownReply("内容"), true},
+		{"markdown at limit", group, NewMarkdownReply(strings.Repeat("x", MaxStreamContentBytes)), true},
+		{"empty markdown", group, NewMarkdownReply(""), false},
+		{"nil markdown", group, &PassiveReply{MsgType: ReplyMsgTypeMarkdown}, false},
+		{"markdown too long", group, NewMarkdownReply(strings.Repeat("x", MaxStreamContentBytes+1)), false},
+		{"card in single chat", single, NewTemplateCardReply(card), true},
+		{"card in group chat", group, NewTemplateCardReply(card), false},
+		{"text", single, NewTextReply("内容"), false},
+		{"stream", single, NewStreamReply("s", "内容", true), false},
+		{"card update", single, NewUpdateTemplateCardReply(nil, card), false},
+		{"empty", single, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckActiveReply(tt.cb, tt.reply)
+			if (err == nil) != tt.ok {
+				t.Errorf("CheckActiveReply = %v, want ok=%v", err, tt.ok)
+			}
+			if err != nil && !errors.Is(err, ErrIllegalReply) {
+				t.Errorf("error %v does not wrap ErrIllegalReply", err)
+			}
+		})
+	}
+}
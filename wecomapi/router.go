@@ -0,0 +1,92 @@
+package wecomapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrNoHandler 没有匹配到回调处理函数。
+var ErrNoHandler = errors.New("wecomapi: no handler for callback")
+
+// HandlerFunc 处理回调并返回被动回复，返回nil表示空回复。
+type HandlerFunc func(ctx context.Context, cb *Callback) (*PassiveReply, error)
+
+// Router 按消息类型和事件类型分发回调。
+type Router struct {
+	msgHandlers   map[CallbackMsgType]HandlerFunc
+	eventHandlers map[EventType]HandlerFunc
+	unknown       HandlerFunc
+	notFound      HandlerFunc
+}
+
+// NewRouter 创建回调路由。
+func NewRouter() *Router {
+	return &Router{
+		msgHandlers:   make(map[CallbackMsgType]HandlerFunc),
+		eventHandlers: make(map[EventType]HandlerFunc),
+	}
+}
+
+// HandleMsg 注册消息类型的处理函数。
+func (r *Router) HandleMsg(msgType CallbackMsgType, h HandlerFunc) {
+	r.msgHandlers[msgType] = h
+}
+
+// HandleEvent 注册事件类型的处理函数。
+func (r *Router) HandleEvent(eventType EventType, h HandlerFunc) {
+	r.eventHandlers[eventType] = h
+}
+
+// HandleUnknown 注册未知消息类型的处理函数，原始内容见 Callback.Unknown。
+// 已通过 HandleMsg 显式注册的类型不会进入该处理函数。
+func (r *Router) HandleUnknown(h HandlerFunc) {
+	r.unknown = h
+}
+
+// NotFound 设置未匹配到处理函数时的兜底处理。
+func (r *Router) NotFound(h HandlerFunc) {
+	r.notFound = h
+}
+
+// Dispatch 将回调分发给对应的处理函数。
+func (r *Router) Dispatch(ctx context.Context, cb *Callback) (*PassiveReply, error) {
+	if h := r.match(cb); h != nil {
+		return h(ctx, cb)
+	}
+	if r.notFound != nil {
+		return r.notFound(ctx, cb)
+	}
+	return nil, fmt.Errorf("%w: msgtype=%s", ErrNoHandler, cb.MsgType)
+}
+
+func (r *Router) match(cb *Callback) HandlerFunc {
+	if cb.MsgType == CallbackMsgTypeEvent && cb.Event != nil {
+		if h, ok := r.eventHandlers[cb.Event.EventType]; ok {
+			return h
+		}
+	}
+	if h, ok := r.msgHandlers[cb.MsgType]; ok {
+		return h
+	}
+	if !cb.IsKnownType() {
+		return r.unknown
+	}
+	return nil
+}
+
+// EventHandlerFunc 带类型化事件数据的处理函数。
+type EventHandlerFunc[T any] func(ctx context.Context, cb *Callback, payload *T) (*PassiveReply, error)
+
+// HandleTypedEvent 注册事件类型及其数据结构，并以解码后的数据调用处理函数。
+// 与 RegisterEvent 相同，事件类型已注册为不同结构时panic。
+func HandleTypedEvent[T any](r *Router, eventType EventType, h EventHandlerFunc[T]) {
+	RegisterEvent[T](eventType)
+	r.HandleEvent(eventType, func(ctx context.Context, cb *Callback) (*PassiveReply, error) {
+		payload, err := DecodeEvent[T](cb.Event)
+		if err != nil {
+			return nil, err
+		}
+		return h(ctx, cb, payload)
+	})
+}
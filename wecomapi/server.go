@@ -0,0 +1,158 @@
+package wecomapi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-sphere/wecom-bot-api/wecomcrypt"
+)
+
+// maxCallbackBodySize 回调请求体的最大字节数。
+const maxCallbackBodySize = 1 << 20
+
+// Server 接收企业微信回调的HTTP处理器，负责验签、解密、分发与加密回复。
+type Server struct {
+	crypt    *wecomcrypt.WXBizMsgCrypt
+	handler  HandlerFunc
+	validate bool
+	debug    bool
+	onError  func(r *http.Request, err error)
+}
+
+// ServerOption 配置 Server。
+type ServerOption func(*Server)
+
+// WithValidation 在分发前调用 Callback.Validate，校验失败的回调直接返回400。
+func WithValidation() ServerOption {
+	return func(s *Server) {
+		s.validate = true
+	}
+}
+
+// WithDebug 在发送前调用 CheckReply 检查回复与回调场景是否匹配，
+// 不合法的回复不会发送给企业微信，而是返回500并通过错误回调报告原因。
+func WithDebug() ServerOption {
+	return func(s *Server) {
+		s.debug = true
+	}
+}
+
+// WithErrorHandler 设置处理过程中出错时的回调，用于日志记录。
+func WithErrorHandler(fn func(r *http.Request, err error)) ServerOption {
+	return func(s *Server) {
+		s.onError = fn
+	}
+}
+
+// NewServer 创建回调HTTP处理器，handler通常为 Router.Dispatch。
+func NewServer(cfg Config, handler HandlerFunc, opts ...ServerOption) (*Server, error) {
+	crypt, err := wecomcrypt.NewWXBizMsgCrypt(cfg.Token, cfg.AESKey, cfg.ReceiveID, wecomcrypt.JSONProtocol)
+	if err != nil {
+		return nil, err
+	}
+	s := &Server{
+		crypt:   crypt,
+		handler: handler,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s, nil
+}
+
+// ServeHTTP 处理URL验证（GET）和回调推送（POST）。
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.serveVerify(w, r)
+	case http.MethodPost:
+		s.serveCallback(w, r)
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) serveVerify(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	msg, err := s.crypt.VerifyURL(q.Get("msg_signature"), q.Get("timestamp"), q.Get("nonce"), q.Get("echostr"))
+	if err != nil {
+		s.fail(w, r, http.StatusForbidden, err)
+		return
+	}
+	_, _ = w.Write(msg)
+}
+
+func (s *Server) serveCallback(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxCallbackBodySize))
+	if err != nil {
+		s.fail(w, r, http.StatusBadRequest, err)
+		return
+	}
+	msg, err := s.crypt.DecryptMessage(q.Get("msg_signature"), q.Get("timestamp"), q.Get("nonce"), body)
+	if err != nil {
+		s.fail(w, r, http.StatusForbidden, err)
+		return
+	}
+	cb, err := s.decode(msg)
+	if err != nil {
+		s.fail(w, r, http.StatusBadRequest, err)
+		return
+	}
+	reply, err := s.handler(r.Context(), cb)
+	if err != nil {
+		s.fail(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	if s.debug {
+		if err := CheckReply(cb, reply); err != nil {
+			s.fail(w, r, http.StatusInternalServerError, err)
+			return
+		}
+	}
+	s.reply(w, r, q.Get("nonce"), reply)
+}
+
+func (s *Server) decode(msg []byte) (*Callback, error) {
+	var cb Callback
+	if err := json.Unmarshal(msg, &cb); err != nil {
+		return nil, err
+	}
+	if s.validate {
+		if err := cb.Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return &cb, nil
+}
+
+func (s *Server) reply(w http.ResponseWriter, r *http.Request, nonce string, reply *PassiveReply) {
+	if reply == nil {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	data, err := json.Marshal(reply)
+	if err != nil {
+		s.fail(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	encrypted, err := s.crypt.EncryptMessage(string(data), timestamp, nonce)
+	if err != nil {
+		s.fail(w, r, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(encrypted)
+}
+
+func (s *Server) fail(w http.ResponseWriter, r *http.Request, code int, err error) {
+	if s.onError != nil {
+		s.onError(r, err)
+	}
+	http.Error(w, http.StatusText(code), code)
+}
@@ -0,0 +1,239 @@
+package wecomapi
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+// OverflowPolicy 流式消息内容超出字节上限时的处理策略。
+type OverflowPolicy int
+
+const (
+	// OverflowTruncate 在安全位置截断内容并追加截断提示，丢弃后续内容。
+	OverflowTruncate OverflowPolicy = iota
+	// OverflowContinue 在安全位置截断内容并追加续接提示，后续内容保留，
+	// 流式消息结束后通过 SendOverflow 以主动回复发送。
+	OverflowContinue
+)
+
+// String 返回策略名称。
+func (p OverflowPolicy) String() string {
+	switch p {
+	case OverflowTruncate:
+		return "truncate"
+	case OverflowContinue:
+		return "continue"
+	}
+	return "unknown"
+}
+
+// 默认的超限提示。
+const (
+	DefaultTruncateNotice = "\n\n……（内容过长，已截断）"
+	DefaultContinueNotice = "\n\n……（内容过长，剩余部分将在下一条消息中发送）"
+)
+
+// ErrNoOverflow 流式消息没有需要续接发送的内容。
+var ErrNoOverflow = errors.New("wecomapi: stream has no overflow content")
+
+// WithOverflowPolicy 设置内容超出字节上限时的处理策略，默认为 OverflowTruncate。
+func WithOverflowPolicy(policy OverflowPolicy) StreamOption {
+	return func(w *StreamWriter) {
+		w.policy = policy
+	}
+}
+
+// WithOverflowNotice 设置超限时追加在内容末尾的提示。
+func WithOverflowNotice(notice string) StreamOption {
+	return func(w *StreamWriter) {
+		w.notice = notice
+		w.noticeSet = true
+	}
+}
+
+// WithStreamLimit 设置内容字节上限，默认且最大为 MaxStreamContentBytes。
+func WithStreamLimit(n int) StreamOption {
+	return func(w *StreamWriter) {
+		if n > 0 && n <= MaxStreamContentBytes {
+			w.limit = n
+		}
+	}
+}
+
+// StreamStats 流式消息的字节统计。
+type StreamStats struct {
+	Policy     OverflowPolicy // 超限处理策略
+	Limit      int            // 内容字节上限
+	Written    int            // 累计写入的回答字节数
+	Content    int            // 当前内容的字节数（含思考过程和超限提示）
+	Reasoning  int            // 累计写入的思考过程字节数
+	Overflow   int            // 超出上限、未在流式消息中展示的字节数
+	Dropped    int            // 剩余内容超出一条主动回复的上限、SendOverflow 时将被截断的字节数
+	Overflowed bool           // 是否发生超限
+}
+
+// Stats 返回当前的字节统计。
+func (w *StreamWriter) Stats() StreamStats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, dropped := w.overflowMessageLocked()
+	return StreamStats{
+		Policy:     w.policy,
+		Limit:      w.limit,
+		Written:    w.written,
+		Content:    len(w.renderLocked()),
+		Overflow:   w.written - w.kept,
+		Dropped:    dropped,
+		Overflowed: w.overflowed,
+		Reasoning:  w.reasoning.Len(),
+	}
+}
+
+// Overflowed 判断内容是否超出字节上限。
+func (w *StreamWriter) Overflowed() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.overflowed
+}
+
+// OverflowContent 返回 OverflowContinue 策略下未在流式消息中展示的剩余内容。
+func (w *StreamWriter) OverflowContent() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.rest.String()
+}
+
+// SendOverflow 将剩余内容以Markdown主动回复发送到触发流式消息的回调的 response_url，
+// 应在流式消息结束后调用。response_url 只能使用一次，剩余内容仍超出上限时会被截断，
+// 截断的字节数见 StreamStats.Dropped。
+func (w *StreamWriter) SendOverflow(ctx context.Context, client *ActiveReplyClient, cb *Callback) error {
+	w.mu.Lock()
+	msg, _ := w.overflowMessageLocked()
+	w.mu.Unlock()
+	if msg == "" {
+		return ErrNoOverflow
+	}
+	return client.SendFor(ctx, cb, NewMarkdownReply(msg))
+}
+
+// overflowMessageLocked 返回续接发送的内容及其中被截断的字节数，调用方需持有锁。
+func (w *StreamWriter) overflowMessageLocked() (msg string, dropped int) {
+	rest := w.rest.String()
+	if len(rest) <= MaxStreamContentBytes {
+		return rest, 0
+	}
+	head, fence := cutMarkdown(rest, MaxStreamContentBytes-len(DefaultTruncateNotice))
+	return head + closeFence(fence) + DefaultTruncateNotice, len(rest) - len(head)
+}
+
+func (w *StreamWriter) overflowNotice() string {
+	if w.noticeSet {
+		return w.notice
+	}
+	if w.policy == OverflowContinue {
+		return DefaultContinueNotice
+	}
+	return DefaultTruncateNotice
+}
+
+// appendLocked 追加内容并处理超限，调用方需持有锁。
+func (w *StreamWriter) appendLocked(s string) {
+	w.written += len(s)
+	if w.overflowed {
+		if w.policy == OverflowContinue {
+			w.rest.WriteString(s)
+		}
+		return
+	}
+	limit := w.answerLimit()
+	full := w.buf.String() + s
+	if len(full) <= limit && len(full)+len(closeFence(openFence(full))) <= limit {
+		w.buf.WriteString(s)
+		w.kept += len(s)
+		return
+	}
+	notice := w.overflowNotice()
+	head, fence := cutMarkdown(full, limit-len(notice))
+	w.overflowed = true
+	w.kept = len(head)
+	w.buf.Reset()
+	w.buf.WriteString(head)
+	w.buf.WriteString(closeFence(fence))
+	w.buf.WriteString(notice)
+	if w.policy == OverflowContinue {
+		if fence != "" {
+			w.rest.WriteString(fence + "\n")
+		}
+		w.rest.WriteString(strings.TrimLeft(full[len(head):], "\n"))
+	}
+}
+
+// cutMarkdown 选择安全的截断位置，使head加上闭合代码块的内容不超过n字节：
+// 优先段落或行边界，且不拆分UTF-8字符。
+// fence 为截断处仍未闭合的代码块起始行，为空表示不在代码块内。
+func cutMarkdown(s string, n int) (head, fence string) {
+	budget := n
+	for {
+		head, fence = cutAt(s, budget)
+		end := len(head) + len(closeFence(fence))
+		if end <= n || budget <= 0 {
+			return head, fence
+		}
+		budget -= end - n
+	}
+}
+
+// cutAt 在n字节以内选择安全的截断位置。
+func cutAt(s string, n int) (head, fence string) {
+	if len(s) <= n {
+		return s, openFence(s)
+	}
+	n = max(n, 0)
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	cut := n
+	if i := strings.LastIndex(s[:n], "\n\n"); i > n/2 {
+		cut = i
+	} else if i := strings.LastIndexByte(s[:n], '\n'); i > n/2 {
+		cut = i
+	}
+	head = s[:cut]
+	return head, openFence(head)
+}
+
+// openFence 返回未闭合的代码块起始行。只有由相同字符组成、且不短于起始标记的行才能闭合代码块。
+func openFence(s string) string {
+	var fence string
+	for line := range strings.Lines(s) {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "```") && !strings.HasPrefix(line, "~~~") {
+			continue
+		}
+		if fence == "" {
+			fence = line
+		} else if marker := fenceMarker(fence); len(line) >= len(marker) && strings.Trim(line, marker[:1]) == "" {
+			fence = ""
+		}
+	}
+	return fence
+}
+
+// fenceMarker 返回代码块起始行开头的标记，如 "```" 或 "~~~~"。
+func fenceMarker(fence string) string {
+	if fence == "" {
+		return ""
+	}
+	n := len(fence) - len(strings.TrimLeft(fence, fence[:1]))
+	return fence[:n]
+}
+
+// closeFence 返回闭合代码块的内容，与起始行使用相同的标记字符和长度；fence为空时返回空字符串。
+func closeFence(fence string) string {
+	if fence == "" {
+		return ""
+	}
+	return "\n" + fenceMarker(fence)
+}
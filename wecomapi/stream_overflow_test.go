@@ -0,0 +1,188 @@
+package wecomapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestStreamOverflowTruncate(t *testing.T) {
+	w := NewStreamWriter("", WithStreamLimit(200))
+	line := strings.Repeat("x", 39) + "\n"
+	for range 10 {
+		_, _ = w.WriteString(line)
+	}
+	content := w.Content()
+	if len(content) > 200 {
+		t.Fatalf("content is %d bytes, limit 200", len(content))
+	}
+	if !strings.HasSuffix(content, DefaultTruncateNotice) {
+		t.Errorf("content %q lacks truncate notice", content)
+	}
+	head := strings.TrimSuffix(content, DefaultTruncateNotice)
+	if !strings.HasSuffix(head, "x") || strings.Contains(head, "\n\n") {
+		t.Errorf("content not cut at a line boundary: %q", head)
+	}
+	st := w.Stats()
+	if !st.Overflowed || st.Written != 400 || st.Overflow != 400-len(head) {
+		t.Errorf("stats = %+v, kept %d", st, len(head))
+	}
+	if w.OverflowContent() != "" {
+		t.Error("truncate policy kept overflow content")
+	}
+	if err := w.SendOverflow(context.Background(), NewActiveReplyClient(nil), &Callback{ResponseURL: "http://invalid"}); !errors.Is(err, ErrNoOverflow) {
+		t.Errorf("SendOverflow = %v, want ErrNoOverflow", err)
+	}
+}
+
+func TestStreamOverflowContinue(t *testing.T) {
+	w := NewStreamWriter("", WithStreamLimit(200), WithOverflowPolicy(OverflowContinue))
+	var want strings.Builder
+	for i := range 20 {
+		s := strings.Repeat(string(rune('a'+i)), 19) + "\n"
+		want.WriteString(s)
+		_, _ = w.WriteString(s)
+	}
+	content := w.Content()
+	if len(content) > 200 || !strings.HasSuffix(content, DefaultContinueNotice) {
+		t.Fatalf("content = %q", content)
+	}
+	head := strings.TrimSuffix(content, DefaultContinueNotice)
+	if got := head + "\n" + w.OverflowContent(); got != want.String() {
+		t.Errorf("head + rest != written\n got: %q\nwant: %q", got, want.String())
+	}
+	if st := w.Stats(); st.Dropped != 0 {
+		t.Errorf("Dropped = %d, want 0", st.Dropped)
+	}
+}
+
+func TestStreamOverflowCodeFence(t *testing.T) {
+	w := NewStreamWriter("", WithStreamLimit(120), WithOverflowPolicy(OverflowContinue))
+	_, _ = w.WriteString("intro\n\n```go\n")
+	for range 10 {
+		_, _ = w.WriteString("fmt.Println(1)\n")
+	}
+	_, _ = w.WriteString("```\n")
+	content := w.Content()
+	if len(content) > 120 {
+		t.Fatalf("content is %d bytes", len(content))
+	}
+	if fence := openFence(strings.TrimSuffix(content, DefaultContinueNotice)); fence != "" {
+		t.Errorf("content leaves fence %q open: %q", fence, content)
+	}
+	rest := w.OverflowContent()
+	if !strings.HasPrefix(rest, "```go\n") {
+		t.Errorf("overflow does not reopen the fence: %q", rest)
+	}
+	if openFence(rest) != "" {
+		t.Errorf("overflow leaves fence open: %q", rest)
+	}
+}
+
+func TestStreamOverflowFenceMarkers(t *testing.T) {
+	tests := []struct {
+		name   string
+		open   string
+		inner  string
+		closer string
+	}{
+		{"backticks", "```go", "x := 1", "\n```\n"},
+		{"tildes", "~~~", "x := 1", "\n~~~\n"},
+		{"long backticks", "````md", "```\ninner\n```", "\n````\n"},
+		{"long tildes", "~~~~~", "~~~\ninner", "\n~~~~~\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewStreamWriter("", WithStreamLimit(150), WithOverflowPolicy(OverflowContinue))
+			_, _ = w.WriteString("intro\n\n" + tt.open + "\n")
+			for range 20 {
+				_, _ = w.WriteString(tt.inner + "\n")
+			}
+			content := w.Content()
+			if len(content) > 150 {
+				t.Fatalf("content is %d bytes", len(content))
+			}
+			head := strings.TrimSuffix(content, DefaultContinueNotice)
+			if fence := openFence(head); fence != "" {
+				t.Errorf("content leaves fence %q open: %q", fence, content)
+			}
+			if !strings.HasSuffix(head, tt.closer[:len(tt.closer)-1]) {
+				t.Errorf("content not closed with %q: %q", tt.closer, head)
+			}
+			if rest := w.OverflowContent(); !strings.HasPrefix(rest, tt.open+"\n") {
+				t.Errorf("overflow does not reopen the fence: %q", rest)
+			}
+		})
+	}
+}
+
+func TestStreamRenderClosesFence(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"```go\nx", "```go\nx\n```"},
+		{"~~~\nx", "~~~\nx\n~~~"},
+		{"````\n```\nx", "````\n```\nx\n````"},
+		{"~~~~\nx\n~~~", "~~~~\nx\n~~~\n~~~~"},
+		{"```\nx\n```", "```\nx\n```"},
+		{"~~~\nx\n~~~~", "~~~\nx\n~~~~"},
+	}
+	for _, tt := range tests {
+		w := NewStreamWriter("")
+		_, _ = w.WriteString(tt.in)
+		if got := w.Content(); got != tt.want {
+			t.Errorf("Content(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestStreamOverflowUTF8(t *testing.T) {
+	w := NewStreamWriter("", WithStreamLimit(100), WithOverflowNotice("…"))
+	_, _ = w.WriteString(strings.Repeat("中文", 40))
+	content := w.Content()
+	if len(content) > 100 || !utf8.ValidString(content) {
+		t.Errorf("content = %q (%d bytes)", content, len(content))
+	}
+}
+
+func TestStreamSendOverflowDropped(t *testing.T) {
+	var sent PassiveReply
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&sent); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		_, _ = rw.Write([]byte(`{"errcode":0}`))
+	}))
+	defer srv.Close()
+
+	w := NewStreamWriter("", WithOverflowPolicy(OverflowContinue))
+	line := strings.Repeat("y", 99) + "\n"
+	for range 500 {
+		_, _ = w.WriteString(line)
+	}
+	_ = w.Close()
+	rest := w.OverflowContent()
+	st := w.Stats()
+	if st.Dropped == 0 || st.Dropped >= len(rest) {
+		t.Fatalf("Dropped = %d for %d bytes of overflow", st.Dropped, len(rest))
+	}
+	if err := w.SendOverflow(context.Background(), NewActiveReplyClient(srv.Client(), WithActiveReplyCheck()), &Callback{ChatType: ChatTypeGroup, ResponseURL: srv.URL}); err != nil {
+		t.Fatal(err)
+	}
+	if sent.Markdown == nil {
+		t.Fatalf("sent %+v, want markdown", sent)
+	}
+	msg := sent.Markdown.Content
+	if len(msg) > MaxStreamContentBytes || !strings.HasSuffix(msg, DefaultTruncateNotice) {
+		t.Errorf("sent %d bytes, suffix %q", len(msg), msg[max(len(msg)-40, 0):])
+	}
+	if kept := len(strings.TrimSuffix(msg, DefaultTruncateNotice)); kept+st.Dropped != len(rest) {
+		t.Errorf("kept %d + dropped %d != overflow %d", kept, st.Dropped, len(rest))
+	}
+}
@@ -0,0 +1,195 @@
+package wecomapi
+
+import (
+	"io"
+	"strings"
+)
+
+// 思考过程标签，客户端会将其中的内容展示为模型的思考过程。
+const (
+	thinkOpen  = "<think>"
+	thinkClose = "</think>"
+)
+
+// DefaultReasoningSummary 思考过程折叠后展示的默认内容。
+const DefaultReasoningSummary = "思考完成"
+
+// minReasoningBytes 存在思考过程时为其预留的最小字节数。
+const minReasoningBytes = 256
+
+// reasoningEllipsis 思考过程被截断时追加的省略号。
+const reasoningEllipsis = "……"
+
+// WithReasoningCap 限制思考过程展示的字节数，超出部分以省略号代替。
+func WithReasoningCap(n int) StreamOption {
+	return func(w *StreamWriter) {
+		w.reasoningCap = n
+	}
+}
+
+// WithReasoningCollapse 设置回答开始后将思考过程折叠为summary，summary为空时使用 DefaultReasoningSummary。
+func WithReasoningCollapse(summary string) StreamOption {
+	return func(w *StreamWriter) {
+		if summary == "" {
+			summary = DefaultReasoningSummary
+		}
+		w.collapse = summary
+	}
+}
+
+// WriteReasoning 追加思考过程的增量内容。思考过程始终渲染在回答之前的同一个
+// <think></think> 块中，即使与回答交替写入；其中的思考标签在渲染时去除，可跨多次写入。
+func (w *StreamWriter) WriteReasoning(s string) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.finished {
+		return 0, ErrStreamClosed
+	}
+	w.reasoning.WriteString(s)
+	return len(s), nil
+}
+
+var thinkTagReplacer = strings.NewReplacer(thinkOpen, "", thinkClose, "")
+
+// stripThinkTags 去除思考过程中的思考标签，以及末尾尚不完整的标签前缀。
+func stripThinkTags(s string) string {
+	for strings.Contains(s, thinkOpen) || strings.Contains(s, thinkClose) {
+		s = thinkTagReplacer.Replace(s)
+	}
+	return s[:len(s)-max(partialSuffix(s, thinkOpen), partialSuffix(s, thinkClose))]
+}
+
+// ReasoningWriter 返回写入思考过程的 io.Writer。
+func (w *StreamWriter) ReasoningWriter() io.Writer {
+	return reasoningWriter{w}
+}
+
+type reasoningWriter struct {
+	w *StreamWriter
+}
+
+func (r reasoningWriter) Write(p []byte) (int, error) {
+	if _, err := r.w.WriteReasoning(string(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// answerLimit 返回回答可用的字节数，为思考过程预留空间，调用方需持有锁。
+// 闭合代码块所需的字节数也计入其中。
+func (w *StreamWriter) answerLimit() int {
+	if w.reasoning.Len() == 0 {
+		return w.limit
+	}
+	return w.limit - len(thinkOpen) - len(thinkClose) - len("\n") - minReasoningBytes
+}
+
+// renderLocked 渲染思考过程和回答，总长度不超过字节上限，调用方需持有锁。
+// 回答中未闭合的代码块会被临时闭合，以免输出过程中的内容渲染错乱。
+func (w *StreamWriter) renderLocked() string {
+	answer := w.buf.String()
+	if !w.overflowed {
+		answer += closeFence(openFence(answer))
+	}
+	if w.reasoning.Len() == 0 {
+		return answer
+	}
+	reasoning := stripThinkTags(w.reasoning.String())
+	if w.collapse != "" && w.written > 0 {
+		reasoning = w.collapse
+	}
+	if reasoning == "" {
+		return answer
+	}
+	avail := w.limit - len(answer) - len(thinkOpen) - len(thinkClose) - len("\n")
+	if w.reasoningCap > 0 {
+		avail = min(avail, w.reasoningCap)
+	}
+	if len(reasoning) > avail {
+		if avail <= len(reasoningEllipsis) {
+			return answer
+		}
+		reasoning = truncateBytes(reasoning, avail-len(reasoningEllipsis)) + reasoningEllipsis
+	}
+	return thinkOpen + reasoning + thinkClose + "\n" + answer
+}
+
+// ThinkTagWriter 将内联 <think></think> 标签的单一输出拆分为思考过程和回答，
+// 适用于将思考过程直接输出在内容中的模型。标签可跨多次写入，不可并发使用。
+type ThinkTagWriter struct {
+	w        *StreamWriter
+	thinking bool
+	pending  string // 可能是标签前缀的未决内容
+}
+
+// TaggedWriter 返回拆分内联 <think> 标签的写入器。
+func (w *StreamWriter) TaggedWriter() *ThinkTagWriter {
+	return &ThinkTagWriter{w: w}
+}
+
+// Write 写入可能包含 <think> 标签的增量内容。
+func (t *ThinkTagWriter) Write(p []byte) (int, error) {
+	if _, err := t.WriteString(string(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// WriteString 写入可能包含 <think> 标签的增量内容。
+func (t *ThinkTagWriter) WriteString(s string) (int, error) {
+	n := len(s)
+	s = t.pending + s
+	t.pending = ""
+	for s != "" {
+		tag := thinkOpen
+		if t.thinking {
+			tag = thinkClose
+		}
+		i := strings.Index(s, tag)
+		if i < 0 {
+			keep := partialSuffix(s, tag)
+			t.pending = s[len(s)-keep:]
+			s = s[:len(s)-keep]
+			if err := t.emit(s); err != nil {
+				return 0, err
+			}
+			break
+		}
+		if err := t.emit(s[:i]); err != nil {
+			return 0, err
+		}
+		t.thinking = !t.thinking
+		s = s[i+len(tag):]
+	}
+	return n, nil
+}
+
+// Flush 写出未决的内容，应在输出结束后调用。
+func (t *ThinkTagWriter) Flush() error {
+	s := t.pending
+	t.pending = ""
+	return t.emit(s)
+}
+
+func (t *ThinkTagWriter) emit(s string) error {
+	if s == "" {
+		return nil
+	}
+	var err error
+	if t.thinking {
+		_, err = t.w.WriteReasoning(s)
+	} else {
+		_, err = t.w.WriteString(s)
+	}
+	return err
+}
+
+// partialSuffix 返回s末尾可能是tag前缀的字节数。
+func partialSuffix(s, tag string) int {
+	for n := min(len(s), len(tag)-1); n > 0; n-- {
+		if strings.HasSuffix(s, tag[:n]) {
+			return n
+		}
+	}
+	return 0
+}
@@ -0,0 +1,79 @@
+package wecomapi
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWriteReasoningSplitTags(t *testing.T) {
+	tests := []struct {
+		name   string
+		deltas []string
+		want   string
+	}{
+		{"close tag split", []string{"a</thi", "nk>b"}, "<think>ab</think>\n"},
+		{"open tag split", []string{"<th", "ink>a", "b"}, "<think>ab</think>\n"},
+		{"nested tag", []string{"a<th<think>ink>b"}, "<think>ab</think>\n"},
+		{"pending suffix hidden", []string{"a</thi"}, "<think>a</think>\n"},
+		{"only tags", []string{"<think>", "</think>"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewStreamWriter("")
+			for _, d := range tt.deltas {
+				if _, err := w.WriteReasoning(d); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := w.Content(); got != tt.want {
+				t.Errorf("Content() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReasoningBeforeAnswer(t *testing.T) {
+	w := NewStreamWriter("")
+	_, _ = w.WriteReasoning("think 1 ")
+	_, _ = w.WriteString("answer")
+	_, _ = w.WriteReasoning("think 2")
+	if got, want := w.Content(), "<think>think 1 think 2</think>\nanswer"; got != want {
+		t.Errorf("Content() = %q, want %q", got, want)
+	}
+
+	w = NewStreamWriter("", WithReasoningCollapse(""))
+	_, _ = w.WriteReasoning("long thoughts")
+	if !strings.Contains(w.Content(), "long thoughts") {
+		t.Errorf("reasoning collapsed before answer: %q", w.Content())
+	}
+	_, _ = w.WriteString("answer")
+	if got, want := w.Content(), "<think>"+DefaultReasoningSummary+"</think>\nanswer"; got != want {
+		t.Errorf("Content() = %q, want %q", got, want)
+	}
+}
+
+func TestReasoningCap(t *testing.T) {
+	w := NewStreamWriter("", WithReasoningCap(20))
+	_, _ = w.WriteReasoning(strings.Repeat("r", 100))
+	got := w.Content()
+	want := "<think>" + strings.Repeat("r", 20-len(reasoningEllipsis)) + reasoningEllipsis + "</think>\n"
+	if got != want {
+		t.Errorf("Content() = %q, want %q", got, want)
+	}
+}
+
+func TestThinkTagWriter(t *testing.T) {
+	w := NewStreamWriter("")
+	tw := w.TaggedWriter()
+	for _, d := range []string{"<thi", "nk>plan", "ning</th", "ink>ans", "wer <b"} {
+		if _, err := tw.WriteString(d); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := w.Content(), "<think>planning</think>\nanswer <b"; got != want {
+		t.Errorf("Content() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,202 @@
+package wecomapi
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// MaxStreamContentBytes 流式消息内容的最大字节数。
+const MaxStreamContentBytes = 20480
+
+// MaxStreamImages 流式消息结束时最多可附带的图片数。
+const MaxStreamImages = 10
+
+var (
+	// ErrStreamClosed 向已结束的流式消息写入内容。
+	ErrStreamClosed = errors.New("wecomapi: stream closed")
+	// ErrStreamFeedbackLate 在已发出首次回复后设置反馈ID。
+	ErrStreamFeedbackLate = errors.New("wecomapi: stream feedback must be set before the first reply")
+)
+
+// StreamOption 配置 StreamWriter 或其结束时的附加内容。
+type StreamOption func(*StreamWriter)
+
+// WithStreamImages 设置流式消息结束时附带的图片，仅在 finish=true 的回复中发送。
+func WithStreamImages(images ...*ImageBase64) StreamOption {
+	return func(w *StreamWriter) {
+		for _, img := range images {
+			w.items = append(w.items, StreamMsgItem{MsgType: MsgItemTypeImage, Image: img})
+		}
+	}
+}
+
+// WithStreamFeedback 设置流式消息的反馈ID，用户反馈时会触发 feedback_event 回调。
+// 反馈ID仅在首次回复时生效，应在创建时设置；首次 Snapshot 之后传给 Close 会返回 ErrStreamFeedbackLate。
+func WithStreamFeedback(id string) StreamOption {
+	return func(w *StreamWriter) {
+		w.feedback = &Feedback{ID: id}
+	}
+}
+
+// StreamWriter 将增量内容累积为流式消息的完整内容，可在多个goroutine中并发使用。
+// 每次收到流式消息刷新回调时，调用 Snapshot 获取下一次回复。
+type StreamWriter struct {
+	mu       sync.Mutex
+	id       string
+	buf      strings.Builder
+	finished bool
+	err      error
+	items    []StreamMsgItem
+	feedback *Feedback
+	replied  bool // 已通过 Snapshot 生成过回复
+
+	limit      int
+	policy     OverflowPolicy
+	notice     string
+	noticeSet  bool
+	overflowed bool
+	written    int             // 累计写入字节数
+	kept       int             // 写入内容中保留在buf的字节数
+	rest       strings.Builder // OverflowContinue 策略下的剩余内容
+
+	reasoning    strings.Builder
+	reasoningCap int
+	collapse     string
+}
+
+// NewStreamWriter 创建流式消息写入器，id为空时随机生成。
+func NewStreamWriter(id string, opts ...StreamOption) *StreamWriter {
+	if id == "" {
+		id = newStreamID()
+	}
+	w := &StreamWriter{id: id, limit: MaxStreamContentBytes}
+	for _, opt := range opts {
+		opt(w)
+	}
+	return w
+}
+
+func newStreamID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// ID 返回流式消息ID。
+func (w *StreamWriter) ID() string {
+	return w.id
+}
+
+// Write 追加增量内容，超出字节上限时按 OverflowPolicy 处理，流式消息结束后返回 ErrStreamClosed。
+func (w *StreamWriter) Write(p []byte) (int, error) {
+	return w.WriteString(string(p))
+}
+
+// WriteString 追加增量内容，流式消息结束后返回 ErrStreamClosed。
+func (w *StreamWriter) WriteString(s string) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.finished {
+		return 0, ErrStreamClosed
+	}
+	w.appendLocked(s)
+	return len(s), nil
+}
+
+// Content 返回当前流式消息的内容（含思考过程），超限时为截断后的内容。
+func (w *StreamWriter) Content() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.renderLocked()
+}
+
+// Len 返回当前流式消息内容的字节数。
+func (w *StreamWriter) Len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.renderLocked())
+}
+
+// Finished 判断流式消息是否已结束。
+func (w *StreamWriter) Finished() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.finished
+}
+
+// Err 返回 CloseWithError 设置的错误。
+func (w *StreamWriter) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
+
+// Snapshot 返回当前的流式消息回复体，内容为累积的完整内容。
+// 图片仅在流式消息结束后附带。
+func (w *StreamWriter) Snapshot() *StreamReply {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.replied = true
+	s := &StreamReply{
+		ID:       w.id,
+		Finish:   w.finished,
+		Content:  w.renderLocked(),
+		Feedback: w.feedback,
+	}
+	if w.finished && len(w.items) > 0 {
+		s.MsgItem = append([]StreamMsgItem(nil), w.items...)
+	}
+	return s
+}
+
+// Reply 返回当前快照的流式消息被动回复。
+func (w *StreamWriter) Reply() *PassiveReply {
+	return &PassiveReply{
+		MsgType: ReplyMsgTypeStream,
+		Stream:  w.Snapshot(),
+	}
+}
+
+// Close 结束流式消息，opts 可附加最终图片和反馈ID。重复关闭返回 ErrStreamClosed。
+func (w *StreamWriter) Close(opts ...StreamOption) error {
+	return w.close(nil, opts)
+}
+
+// CloseWithError 以错误结束流式消息，并在内容末尾追加错误说明。
+// err 的文本会展示给用户，调用方应先将内部错误映射为用户可读的信息。
+func (w *StreamWriter) CloseWithError(err error, opts ...StreamOption) error {
+	return w.close(err, opts)
+}
+
+func (w *StreamWriter) close(err error, opts []StreamOption) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.finished {
+		return ErrStreamClosed
+	}
+	items, feedback := w.items, w.feedback
+	for _, opt := range opts {
+		opt(w)
+	}
+	if n := len(w.items); n > MaxStreamImages {
+		w.items, w.feedback = items, feedback
+		return fmt.Errorf("wecomapi: stream has %d images, at most %d allowed", n, MaxStreamImages)
+	}
+	if w.replied && w.feedback != feedback {
+		w.items, w.feedback = items, feedback
+		return ErrStreamFeedbackLate
+	}
+	if err != nil {
+		w.err = err
+		if w.written > 0 {
+			w.appendLocked("\n\n")
+		}
+		w.appendLocked(err.Error())
+	}
+	w.finished = true
+	return nil
+}
@@ -0,0 +1,78 @@
+package wecomapi
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestStreamWriterSnapshot(t *testing.T) {
+	img := NewImageBase64([]byte("png"))
+	w := NewStreamWriter("s1", WithStreamFeedback("fb1"))
+	_, _ = w.WriteString("hello ")
+	_, _ = w.Write([]byte("world"))
+
+	s := w.Snapshot()
+	if s.ID != "s1" || s.Finish || s.Content != "hello world" {
+		t.Fatalf("snapshot = %+v", s)
+	}
+	if s.Feedback == nil || s.Feedback.ID != "fb1" {
+		t.Errorf("feedback = %+v", s.Feedback)
+	}
+	if err := w.Close(WithStreamImages(img)); err != nil {
+		t.Fatal(err)
+	}
+	s = w.Snapshot()
+	if !s.Finish || len(s.MsgItem) != 1 || s.MsgItem[0].Image != img {
+		t.Errorf("final snapshot = %+v", s)
+	}
+	if _, err := w.WriteString("x"); !errors.Is(err, ErrStreamClosed) {
+		t.Errorf("write after close = %v", err)
+	}
+	if err := w.Close(); !errors.Is(err, ErrStreamClosed) {
+		t.Errorf("second close = %v", err)
+	}
+}
+
+func TestStreamWriterTooManyImages(t *testing.T) {
+	images := make([]*ImageBase64, MaxStreamImages+2)
+	for i := range images {
+		images[i] = NewImageBase64([]byte{byte(i)})
+	}
+	w := NewStreamWriter("", WithStreamImages(images[:1]...))
+	err := w.Close(WithStreamImages(images[1:]...))
+	if err == nil {
+		t.Fatal("close with too many images succeeded")
+	}
+	if want := "stream has 12 images"; !strings.Contains(err.Error(), want) {
+		t.Errorf("error %q does not contain %q", err, want)
+	}
+	if w.Finished() {
+		t.Error("stream finished despite invalid options")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(w.Snapshot().MsgItem); n != 1 {
+		t.Errorf("images after failed close = %d, want 1", n)
+	}
+}
+
+func TestStreamWriterFeedbackAfterReply(t *testing.T) {
+	w := NewStreamWriter("")
+	if err := w.Close(WithStreamFeedback("early")); err != nil {
+		t.Fatalf("feedback before first reply: %v", err)
+	}
+
+	w = NewStreamWriter("")
+	_ = w.Snapshot()
+	if err := w.Close(WithStreamFeedback("late")); !errors.Is(err, ErrStreamFeedbackLate) {
+		t.Fatalf("feedback after first reply = %v, want ErrStreamFeedbackLate", err)
+	}
+	if w.Snapshot().Feedback != nil {
+		t.Error("late feedback was kept")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
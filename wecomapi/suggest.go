@@ -0,0 +1,71 @@
+package wecomapi
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// 建议问题的限制。
+const (
+	MaxSuggestedQuestions = maxJumpItems // 跳转指引最多3个
+	suggestionTitleChars  = strictJumpTitle
+)
+
+// SuggestedQuestions 将建议问题转换为触发智能回复的跳转指引列表。
+// 忽略空白和重复的问题，最多保留3个；问题按UTF-8边界截断至200字节，
+// 标题为问题截断至建议的13个字。
+func SuggestedQuestions(questions ...string) []JumpAction {
+	jumps := make([]JumpAction, 0, min(len(questions), MaxSuggestedQuestions))
+	seen := make(map[string]bool, len(questions))
+	for _, q := range questions {
+		q = truncateBytes(strings.TrimSpace(q), maxQuestionBytes)
+		if q == "" || seen[q] {
+			continue
+		}
+		seen[q] = true
+		jumps = append(jumps, JumpQuestion(truncateChars(q, suggestionTitleChars), q))
+		if len(jumps) == MaxSuggestedQuestions {
+			break
+		}
+	}
+	return jumps
+}
+
+// NewSuggestionCard 创建带建议问题的文本通知卡片。
+// 文本通知卡片要求整体点击跳转，action 需为URL或小程序跳转。
+func NewSuggestionCard(title string, action *CardAction, questions ...string) (*TemplateCard, error) {
+	return NewTextNoticeCard().
+		MainTitle(title, "").
+		Jump(SuggestedQuestions(questions...)...).
+		CardAction(action).
+		Build()
+}
+
+// NewStreamWithSuggestionsReply 创建附带建议问题卡片的流式消息被动回复。
+func NewStreamWithSuggestionsReply(stream *StreamReply, title string, action *CardAction, questions ...string) (*PassiveReply, error) {
+	card, err := NewSuggestionCard(title, action, questions...)
+	if err != nil {
+		return nil, err
+	}
+	return NewStreamWithTemplateCardReply(stream, card), nil
+}
+
+// truncateBytes 按UTF-8边界将字符串截断至最多n字节。
+func truncateBytes(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// truncateChars 将字符串截断至最多n个字符，截断时以省略号结尾。
+func truncateChars(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	return string(runes[:n-1]) + "…"
+}
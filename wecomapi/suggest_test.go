@@ -0,0 +1,77 @@
+package wecomapi
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestSuggestedQuestions(t *testing.T) {
+	long := strings.Repeat("问", 100) // 300字节
+	tests := []struct {
+		name      string
+		questions []string
+		want      []string
+	}{
+		{"empty", nil, nil},
+		{"trim and skip blank", []string{"  怎么报销？ ", "", "   "}, []string{"怎么报销？"}},
+		{"dedupe", []string{"a", "a", " a ", "b"}, []string{"a", "b"}},
+		{"limit", []string{"1", "2", "3", "4", "5"}, []string{"1", "2", "3"}},
+		{"dedupe before limit", []string{"1", "1", "2", "2", "3"}, []string{"1", "2", "3"}},
+		{"truncate at rune boundary", []string{long}, []string{strings.Repeat("问", maxQuestionBytes/3)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jumps := SuggestedQuestions(tt.questions...)
+			if len(jumps) != len(tt.want) {
+				t.Fatalf("got %d jumps, want %d: %+v", len(jumps), len(tt.want), jumps)
+			}
+			for i, j := range jumps {
+				if j.Type != JumpActionTypeQuestion || j.Question != tt.want[i] {
+					t.Errorf("jumps[%d] = %+v, want question %q", i, j, tt.want[i])
+				}
+				if len(j.Question) > maxQuestionBytes || !utf8.ValidString(j.Question) {
+					t.Errorf("jumps[%d].question is %d bytes or invalid UTF-8", i, len(j.Question))
+				}
+				if n := utf8.RuneCountInString(j.Title); n > strictJumpTitle {
+					t.Errorf("jumps[%d].title has %d characters", i, n)
+				}
+			}
+		})
+	}
+}
+
+func TestSuggestedQuestionTitle(t *testing.T) {
+	jumps := SuggestedQuestions("短问题", "这是一个超过十三个字的非常长的建议问题")
+	if jumps[0].Title != "短问题" {
+		t.Errorf("short title = %q", jumps[0].Title)
+	}
+	if want := "这是一个超过十三个字的非…"; jumps[1].Title != want {
+		t.Errorf("long title = %q, want %q", jumps[1].Title, want)
+	}
+}
+
+func TestNewSuggestionCard(t *testing.T) {
+	card, err := NewSuggestionCard("你可能还想问", CardActionURL("https://example.com"), "a", "b", "c", "d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if card.CardType != TemplateCardTypeTextNotice || len(card.JumpList) != MaxSuggestedQuestions {
+		t.Errorf("card = %+v", card)
+	}
+	if err := card.ValidateStrict(); err != nil {
+		t.Errorf("suggestion card fails ValidateStrict: %v", err)
+	}
+
+	if _, err := NewSuggestionCard("标题", nil, "a"); err == nil {
+		t.Error("card without card_action accepted")
+	}
+
+	reply, err := NewStreamWithSuggestionsReply(&StreamReply{ID: "s", Finish: true, Content: "答案"}, "相关问题", CardActionURL("https://example.com"), "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply.Stream == nil || reply.TemplateCard == nil || len(reply.TemplateCard.JumpList) != 1 {
+		t.Errorf("reply = %+v", reply)
+	}
+}
@@ -0,0 +1,315 @@
+package wecomapi
+
+// cardBuilder 各类型卡片构建器的公共部分，B为具体构建器类型以支持链式调用。
+type cardBuilder[B any] struct {
+	card TemplateCard
+	self B
+}
+
+// Source 设置卡片来源样式。
+func (b *cardBuilder[B]) Source(iconURL, desc string, color SourceDescColor) B {
+	b.card.Source = &Source{IconURL: iconURL, Desc: desc, DescColor: color}
+	return b.self
+}
+
+// MainTitle 设置一级标题和标题辅助信息。
+func (b *cardBuilder[B]) MainTitle(title, desc string) B {
+	b.card.MainTitle = &MainTitle{Title: title, Desc: desc}
+	return b.self
+}
+
+// TaskID 设置任务ID，将在卡片回调事件中返回。
+func (b *cardBuilder[B]) TaskID(taskID string) B {
+	b.card.TaskID = taskID
+	return b.self
+}
+
+// Feedback 设置反馈ID，用户反馈时触发回调事件。
+func (b *cardBuilder[B]) Feedback(id string) B {
+	b.card.Feedback = &Feedback{ID: id}
+	return b.self
+}
+
+// Build 校验卡片并返回副本，缺少必填元素或超出平台限制时返回 ValidationErrors。
+func (b *cardBuilder[B]) Build() (*TemplateCard, error) {
+	card := b.card
+	if err := card.Validate(); err != nil {
+		return nil, err
+	}
+	return &card, nil
+}
+
+// NewActionItem 创建右上角菜单操作项。
+func NewActionItem(text, key string) ActionItem {
+	return ActionItem{Text: text, Key: key}
+}
+
+// JumpURL 创建跳转URL的跳转指引。
+func JumpURL(title, url string) JumpAction {
+	return JumpAction{Type: JumpActionTypeURL, Title: title, URL: url}
+}
+
+// JumpApplet 创建跳转小程序的跳转指引。
+func JumpApplet(title, appID, pagePath string) JumpAction {
+	return JumpAction{Type: JumpActionTypeApplet, Title: title, AppID: appID, PagePath: pagePath}
+}
+
+// JumpQuestion 创建触发智能回复的跳转指引。
+func JumpQuestion(title, question string) JumpAction {
+	return JumpAction{Type: JumpActionTypeQuestion, Title: title, Question: question}
+}
+
+// CardActionURL 创建跳转URL的整体卡片点击事件。
+func CardActionURL(url string) *CardAction {
+	return &CardAction{Type: CardActionTypeURL, URL: url}
+}
+
+// CardActionApplet 创建跳转小程序的整体卡片点击事件。
+func CardActionApplet(appID, pagePath string) *CardAction {
+	return &CardAction{Type: CardActionTypeApplet, AppID: appID, PagePath: pagePath}
+}
+
+// HorizontalText 创建普通文本的二级标题+文本项。
+func HorizontalText(keyName, value string) HorizontalContent {
+	return HorizontalContent{KeyName: keyName, Value: value}
+}
+
+// HorizontalURL 创建跳转URL的二级标题+文本项。
+func HorizontalURL(keyName, value, url string) HorizontalContent {
+	return HorizontalContent{Type: HorizontalContentTypeURL, KeyName: keyName, Value: value, URL: url}
+}
+
+// HorizontalUser 创建跳转成员详情的二级标题+文本项。
+func HorizontalUser(keyName, value, userID string) HorizontalContent {
+	return HorizontalContent{Type: HorizontalContentTypeUserID, KeyName: keyName, Value: value, UserID: userID}
+}
+
+// NewSelectOption 创建下拉选择器选项。
+func NewSelectOption(id, text string) SelectOption {
+	return SelectOption{ID: id, Text: text}
+}
+
+// NewCheckboxOption 创建选择题选项。
+func NewCheckboxOption(id, text string, checked bool) CheckboxOption {
+	return CheckboxOption{ID: id, Text: text, IsChecked: checked}
+}
+
+// NewButton 创建按钮。
+func NewButton(text, key string, style ButtonStyle) Button {
+	return Button{Text: text, Key: key, Style: style}
+}
+
+// TextNoticeBuilder 文本通知模板卡片构建器。
+type TextNoticeBuilder struct {
+	cardBuilder[*TextNoticeBuilder]
+}
+
+// NewTextNoticeCard 创建文本通知模板卡片构建器。
+func NewTextNoticeCard() *TextNoticeBuilder {
+	b := &TextNoticeBuilder{}
+	b.card.CardType = TemplateCardTypeTextNotice
+	b.self = b
+	return b
+}
+
+// ActionMenu 设置右上角更多操作按钮，设置后必须指定TaskID。
+func (b *TextNoticeBuilder) ActionMenu(desc string, items ...ActionItem) *TextNoticeBuilder {
+	b.card.ActionMenu = &ActionMenu{Desc: desc, ActionList: items}
+	return b
+}
+
+// Emphasis 设置关键数据样式。
+func (b *TextNoticeBuilder) Emphasis(title, desc string) *TextNoticeBuilder {
+	b.card.EmphasisContent = &EmphasisContent{Title: title, Desc: desc}
+	return b
+}
+
+// QuoteArea 设置引用区域。
+func (b *TextNoticeBuilder) QuoteArea(quote *QuoteArea) *TextNoticeBuilder {
+	b.card.QuoteArea = quote
+	return b
+}
+
+// SubTitle 设置二级普通文本。
+func (b *TextNoticeBuilder) SubTitle(text string) *TextNoticeBuilder {
+	b.card.SubTitleText = text
+	return b
+}
+
+// Horizontal 追加二级标题+文本项。
+func (b *TextNoticeBuilder) Horizontal(items ...HorizontalContent) *TextNoticeBuilder {
+	b.card.HorizontalContentList = append(b.card.HorizontalContentList, items...)
+	return b
+}
+
+// Jump 追加跳转指引。
+func (b *TextNoticeBuilder) Jump(actions ...JumpAction) *TextNoticeBuilder {
+	b.card.JumpList = append(b.card.JumpList, actions...)
+	return b
+}
+
+// CardAction 设置整体卡片点击跳转，text_notice必填。
+func (b *TextNoticeBuilder) CardAction(action *CardAction) *TextNoticeBuilder {
+	b.card.CardAction = action
+	return b
+}
+
+// NewsNoticeBuilder 图文展示模板卡片构建器。
+type NewsNoticeBuilder struct {
+	cardBuilder[*NewsNoticeBuilder]
+}
+
+// NewNewsNoticeCard 创建图文展示模板卡片构建器。
+func NewNewsNoticeCard() *NewsNoticeBuilder {
+	b := &NewsNoticeBuilder{}
+	b.card.CardType = TemplateCardTypeNewsNotice
+	b.self = b
+	return b
+}
+
+// ActionMenu 设置右上角更多操作按钮，设置后必须指定TaskID。
+func (b *NewsNoticeBuilder) ActionMenu(desc string, items ...ActionItem) *NewsNoticeBuilder {
+	b.card.ActionMenu = &ActionMenu{Desc: desc, ActionList: items}
+	return b
+}
+
+// Image 设置图片样式，aspectRatio为0时使用默认值。
+func (b *NewsNoticeBuilder) Image(url string, aspectRatio float64) *NewsNoticeBuilder {
+	b.card.CardImage = &CardImage{URL: url, AspectRatio: aspectRatio}
+	return b
+}
+
+// ImageTextArea 设置左图右文样式。
+func (b *NewsNoticeBuilder) ImageTextArea(area *ImageTextArea) *NewsNoticeBuilder {
+	b.card.ImageTextArea = area
+	return b
+}
+
+// Vertical 追加二级垂直内容。
+func (b *NewsNoticeBuilder) Vertical(title, desc string) *NewsNoticeBuilder {
+	b.card.VerticalContentList = append(b.card.VerticalContentList, VerticalContent{Title: title, Desc: desc})
+	return b
+}
+
+// Horizontal 追加二级标题+文本项。
+func (b *NewsNoticeBuilder) Horizontal(items ...HorizontalContent) *NewsNoticeBuilder {
+	b.card.HorizontalContentList = append(b.card.HorizontalContentList, items...)
+	return b
+}
+
+// Jump 追加跳转指引。
+func (b *NewsNoticeBuilder) Jump(actions ...JumpAction) *NewsNoticeBuilder {
+	b.card.JumpList = append(b.card.JumpList, actions...)
+	return b
+}
+
+// CardAction 设置整体卡片点击跳转，news_notice必填。
+func (b *NewsNoticeBuilder) CardAction(action *CardAction) *NewsNoticeBuilder {
+	b.card.CardAction = action
+	return b
+}
+
+// ButtonInteractionBuilder 按钮交互模板卡片构建器。
+type ButtonInteractionBuilder struct {
+	cardBuilder[*ButtonInteractionBuilder]
+}
+
+// NewButtonInteractionCard 创建按钮交互模板卡片构建器。
+func NewButtonInteractionCard() *ButtonInteractionBuilder {
+	b := &ButtonInteractionBuilder{}
+	b.card.CardType = TemplateCardTypeButtonInteraction
+	b.self = b
+	return b
+}
+
+// ActionMenu 设置右上角更多操作按钮。
+func (b *ButtonInteractionBuilder) ActionMenu(desc string, items ...ActionItem) *ButtonInteractionBuilder {
+	b.card.ActionMenu = &ActionMenu{Desc: desc, ActionList: items}
+	return b
+}
+
+// QuoteArea 设置引用区域。
+func (b *ButtonInteractionBuilder) QuoteArea(quote *QuoteArea) *ButtonInteractionBuilder {
+	b.card.QuoteArea = quote
+	return b
+}
+
+// SubTitle 设置二级普通文本。
+func (b *ButtonInteractionBuilder) SubTitle(text string) *ButtonInteractionBuilder {
+	b.card.SubTitleText = text
+	return b
+}
+
+// Horizontal 追加二级标题+文本项。
+func (b *ButtonInteractionBuilder) Horizontal(items ...HorizontalContent) *ButtonInteractionBuilder {
+	b.card.HorizontalContentList = append(b.card.HorizontalContentList, items...)
+	return b
+}
+
+// Selection 设置下拉式选择器。
+func (b *ButtonInteractionBuilder) Selection(questionKey, title string, options ...SelectOption) *ButtonInteractionBuilder {
+	b.card.ButtonSelection = &SelectionItem{QuestionKey: questionKey, Title: title, OptionList: options}
+	return b
+}
+
+// Button 追加按钮。
+func (b *ButtonInteractionBuilder) Button(text, key string, style ButtonStyle) *ButtonInteractionBuilder {
+	b.card.ButtonList = append(b.card.ButtonList, NewButton(text, key, style))
+	return b
+}
+
+// CardAction 设置整体卡片点击跳转。
+func (b *ButtonInteractionBuilder) CardAction(action *CardAction) *ButtonInteractionBuilder {
+	b.card.CardAction = action
+	return b
+}
+
+// VoteInteractionBuilder 投票选择模板卡片构建器。
+type VoteInteractionBuilder struct {
+	cardBuilder[*VoteInteractionBuilder]
+}
+
+// NewVoteInteractionCard 创建投票选择模板卡片构建器。
+func NewVoteInteractionCard() *VoteInteractionBuilder {
+	b := &VoteInteractionBuilder{}
+	b.card.CardType = TemplateCardTypeVoteInteraction
+	b.self = b
+	return b
+}
+
+// Checkbox 设置选择题。
+func (b *VoteInteractionBuilder) Checkbox(questionKey string, mode CheckboxMode, options ...CheckboxOption) *VoteInteractionBuilder {
+	b.card.Checkbox = &Checkbox{QuestionKey: questionKey, Mode: mode, OptionList: options}
+	return b
+}
+
+// SubmitButton 设置提交按钮。
+func (b *VoteInteractionBuilder) SubmitButton(text, key string) *VoteInteractionBuilder {
+	b.card.SubmitButton = &SubmitButton{Text: text, Key: key}
+	return b
+}
+
+// MultipleInteractionBuilder 多项选择模板卡片构建器。
+type MultipleInteractionBuilder struct {
+	cardBuilder[*MultipleInteractionBuilder]
+}
+
+// NewMultipleInteractionCard 创建多项选择模板卡片构建器。
+func NewMultipleInteractionCard() *MultipleInteractionBuilder {
+	b := &MultipleInteractionBuilder{}
+	b.card.CardType = TemplateCardTypeMultipleInteraction
+	b.self = b
+	return b
+}
+
+// Select 追加下拉式选择器，最多3个。
+func (b *MultipleInteractionBuilder) Select(questionKey, title string, options ...SelectOption) *MultipleInteractionBuilder {
+	b.card.SelectList = append(b.card.SelectList, SelectionItem{QuestionKey: questionKey, Title: title, OptionList: options})
+	return b
+}
+
+// SubmitButton 设置提交按钮。
+func (b *MultipleInteractionBuilder) SubmitButton(text, key string) *MultipleInteractionBuilder {
+	b.card.SubmitButton = &SubmitButton{Text: text, Key: key}
+	return b
+}
@@ -0,0 +1,157 @@
+package wecomapi
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+)
+
+// errorFields 返回校验错误中的字段路径，err为nil时返回nil。
+func errorFields(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("error %v is not ValidationErrors", err)
+	}
+	fields := make([]string, len(errs))
+	for i, fe := range errs {
+		fields[i] = fe.Field
+	}
+	return fields
+}
+
+func TestCardBuilders(t *testing.T) {
+	tests := []struct {
+		name     string
+		build    func() (*TemplateCard, error)
+		cardType TemplateCardType
+	}{
+		{
+			name: "text_notice",
+			build: NewTextNoticeCard().
+				Source("https://example.com/icon.png", "来源", SourceDescColorGreen).
+				MainTitle("标题", "说明").
+				Emphasis("100", "数据").
+				SubTitle("正文").
+				Horizontal(HorizontalText("姓名", "张三"), HorizontalURL("链接", "打开", "https://example.com"), HorizontalUser("成员", "李四", "lisi")).
+				Jump(JumpURL("文档", "https://example.com"), JumpQuestion("问一下", "帮助")).
+				CardAction(CardActionURL("https://example.com")).
+				Build,
+			cardType: TemplateCardTypeTextNotice,
+		},
+		{
+			name: "news_notice",
+			build: NewNewsNoticeCard().
+				MainTitle("标题", "").
+				Image("https://example.com/a.png", 1.5).
+				Vertical("小标题", "内容").
+				Jump(JumpApplet("小程序", "APPID", "pages/index")).
+				CardAction(CardActionApplet("APPID", "pages/index")).
+				Build,
+			cardType: TemplateCardTypeNewsNotice,
+		},
+		{
+			name: "button_interaction",
+			build: NewButtonInteractionCard().
+				MainTitle("审批", "").
+				TaskID("task_1").
+				ActionMenu("更多", NewActionItem("不再提醒", "mute")).
+				Selection("q1", "选择", NewSelectOption("a", "A"), NewSelectOption("b", "B")).
+				Button("同意", "approve", ButtonStyleBlue).
+				Button("拒绝", "reject", ButtonStyleRed).
+				Build,
+			cardType: TemplateCardTypeButtonInteraction,
+		},
+		{
+			name: "vote_interaction",
+			build: NewVoteInteractionCard().
+				MainTitle("投票", "").
+				TaskID("vote-1").
+				Checkbox("q1", CheckboxModeMulti, NewCheckboxOption("a", "A", true), NewCheckboxOption("b", "B", false)).
+				SubmitButton("提交", "submit").
+				Build,
+			cardType: TemplateCardTypeVoteInteraction,
+		},
+		{
+			name: "multiple_interaction",
+			build: NewMultipleInteractionCard().
+				MainTitle("选择", "").
+				Select("q1", "城市", NewSelectOption("bj", "北京")).
+				Select("q2", "部门", NewSelectOption("rd", "研发")).
+				SubmitButton("提交", "submit").
+				Build,
+			cardType: TemplateCardTypeMultipleInteraction,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			card, err := tt.build()
+			if err != nil {
+				t.Fatalf("Build: %v", err)
+			}
+			if card.CardType != tt.cardType {
+				t.Errorf("card_type = %q, want %q", card.CardType, tt.cardType)
+			}
+			if err := card.Validate(); err != nil {
+				t.Errorf("built card fails Validate: %v", err)
+			}
+			data, err := json.Marshal(card)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var decoded TemplateCard
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatal(err)
+			}
+			if err := decoded.Validate(); err != nil {
+				t.Errorf("decoded card fails Validate: %v\n%s", err, data)
+			}
+		})
+	}
+}
+
+func TestCardBuildersRequired(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func() (*TemplateCard, error)
+		want  []string
+	}{
+		{"text_notice", NewTextNoticeCard().Build, []string{"main_title.title|sub_title_text", "card_action"}},
+		{"news_notice", NewNewsNoticeCard().Build, []string{"main_title", "card_image|image_text_area", "card_action"}},
+		{"button_interaction", NewButtonInteractionCard().Build, []string{"main_title", "button_list", "task_id"}},
+		{"vote_interaction", NewVoteInteractionCard().Build, []string{"main_title", "checkbox", "submit_button", "task_id"}},
+		{"multiple_interaction", NewMultipleInteractionCard().Build, []string{"main_title", "select_list", "submit_button"}},
+		{
+			"action_menu needs task_id",
+			NewTextNoticeCard().SubTitle("正文").CardAction(CardActionURL("https://example.com")).ActionMenu("更多", NewActionItem("x", "k")).Build,
+			[]string{"task_id"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			card, err := tt.build()
+			if card != nil {
+				t.Errorf("Build returned a card with errors: %+v", card)
+			}
+			if got := errorFields(t, err); !slices.Equal(got, tt.want) {
+				t.Errorf("fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCardBuilderCopy(t *testing.T) {
+	b := NewButtonInteractionCard().MainTitle("审批", "").TaskID("t1").Button("同意", "ok", ButtonStyleBlue)
+	first, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.MainTitle("已修改", "")
+	if first.MainTitle.Title != "审批" {
+		t.Errorf("builder change leaked into built card: %q", first.MainTitle.Title)
+	}
+}
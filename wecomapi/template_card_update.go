@@ -0,0 +1,148 @@
+package wecomapi
+
+import "slices"
+
+// DefaultSubmittedText 交互后按钮的默认文案。
+const DefaultSubmittedText = "已提交"
+
+// Clone 深拷贝卡片。
+func (c *TemplateCard) Clone() *TemplateCard {
+	if c == nil {
+		return nil
+	}
+	out := *c
+	out.Source = clonePtr(c.Source)
+	if c.ActionMenu != nil {
+		m := *c.ActionMenu
+		m.ActionList = slices.Clone(m.ActionList)
+		out.ActionMenu = &m
+	}
+	out.MainTitle = clonePtr(c.MainTitle)
+	out.EmphasisContent = clonePtr(c.EmphasisContent)
+	out.QuoteArea = clonePtr(c.QuoteArea)
+	out.HorizontalContentList = slices.Clone(c.HorizontalContentList)
+	out.JumpList = slices.Clone(c.JumpList)
+	out.CardAction = clonePtr(c.CardAction)
+	out.CardImage = clonePtr(c.CardImage)
+	out.ImageTextArea = clonePtr(c.ImageTextArea)
+	out.VerticalContentList = slices.Clone(c.VerticalContentList)
+	if c.ButtonSelection != nil {
+		s := c.ButtonSelection.clone()
+		out.ButtonSelection = &s
+	}
+	out.ButtonList = slices.Clone(c.ButtonList)
+	if c.Checkbox != nil {
+		cb := *c.Checkbox
+		cb.OptionList = slices.Clone(cb.OptionList)
+		out.Checkbox = &cb
+	}
+	if c.SelectList != nil {
+		out.SelectList = make([]SelectionItem, len(c.SelectList))
+		for i, s := range c.SelectList {
+			out.SelectList[i] = s.clone()
+		}
+	}
+	out.SubmitButton = clonePtr(c.SubmitButton)
+	out.Feedback = clonePtr(c.Feedback)
+	return &out
+}
+
+func (s SelectionItem) clone() SelectionItem {
+	s.OptionList = slices.Clone(s.OptionList)
+	return s
+}
+
+func clonePtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+// UpdateCardOption 配置 UpdatedCard 的行为。
+type UpdateCardOption func(*updateCardOptions)
+
+type updateCardOptions struct {
+	submittedText string
+	keepEnabled   bool
+	hooks         []func(card *TemplateCard, ev *TemplateCardEvent)
+}
+
+// WithSubmittedText 设置被点击按钮和提交按钮的文案，空字符串表示保留原文案。
+func WithSubmittedText(text string) UpdateCardOption {
+	return func(o *updateCardOptions) {
+		o.submittedText = text
+	}
+}
+
+// WithKeepEnabled 保持选择器和选择题可选，仅回填选中项。
+func WithKeepEnabled() UpdateCardOption {
+	return func(o *updateCardOptions) {
+		o.keepEnabled = true
+	}
+}
+
+// WithCardHook 在默认处理完成后对卡片做进一步修改。
+func WithCardHook(fn func(card *TemplateCard, ev *TemplateCardEvent)) UpdateCardOption {
+	return func(o *updateCardOptions) {
+		o.hooks = append(o.hooks, fn)
+	}
+}
+
+// UpdatedCard 根据卡片事件生成更新后的卡片，原卡片不会被修改。
+// 默认会按 SelectedItems 回填 SelectedID 和 IsChecked、禁用选择器和选择题，
+// 并将被点击的按钮和提交按钮文案改为 DefaultSubmittedText。
+// orig为nil时返回nil，ev为nil时返回未修改的副本。
+func UpdatedCard(orig *TemplateCard, ev *TemplateCardEvent, opts ...UpdateCardOption) *TemplateCard {
+	card := orig.Clone()
+	if card == nil || ev == nil {
+		return card
+	}
+	o := &updateCardOptions{submittedText: DefaultSubmittedText}
+	for _, opt := range opts {
+		opt(o)
+	}
+	card.TaskID = ev.TaskID // 更新卡片时task_id需与回调一致
+	selections := ev.Selections()
+	if s := card.ButtonSelection; s != nil {
+		o.applySelection(s, selections)
+	}
+	for i := range card.SelectList {
+		o.applySelection(&card.SelectList[i], selections)
+	}
+	if cb := card.Checkbox; cb != nil {
+		if ids, ok := selections[cb.QuestionKey]; ok {
+			for i := range cb.OptionList {
+				cb.OptionList[i].IsChecked = slices.Contains(ids, cb.OptionList[i].ID)
+			}
+		}
+		cb.Disable = !o.keepEnabled
+	}
+	if o.submittedText != "" {
+		for i := range card.ButtonList {
+			if card.ButtonList[i].Key == ev.EventKey {
+				card.ButtonList[i].Text = o.submittedText
+			}
+		}
+		if card.SubmitButton != nil && card.SubmitButton.Key == ev.EventKey {
+			card.SubmitButton.Text = o.submittedText
+		}
+	}
+	for _, hook := range o.hooks {
+		hook(card, ev)
+	}
+	return card
+}
+
+func (o *updateCardOptions) applySelection(s *SelectionItem, selections map[string][]string) {
+	if ids := selections[s.QuestionKey]; len(ids) > 0 {
+		s.SelectedID = ids[0]
+	}
+	s.Disable = !o.keepEnabled
+}
+
+// NewUpdatedCardReply 根据卡片事件生成更新卡片的被动回复，参见 UpdatedCard。
+func NewUpdatedCardReply(userIDs []string, orig *TemplateCard, ev *TemplateCardEvent, opts ...UpdateCardOption) *PassiveReply {
+	return NewUpdateTemplateCardReply(userIDs, UpdatedCard(orig, ev, opts...))
+}
@@ -0,0 +1,123 @@
+package wecomapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// fullCard 返回每个可复制字段都已填充的卡片。
+func fullCard() *TemplateCard {
+	return &TemplateCard{
+		CardType:              TemplateCardTypeButtonInteraction,
+		Source:                &Source{Desc: "来源"},
+		ActionMenu:            &ActionMenu{Desc: "更多", ActionList: []ActionItem{{Text: "a", Key: "menu"}}},
+		MainTitle:             &MainTitle{Title: "标题"},
+		EmphasisContent:       &EmphasisContent{Title: "1"},
+		QuoteArea:             &QuoteArea{Title: "引用"},
+		HorizontalContentList: []HorizontalContent{{KeyName: "k", Value: "v"}},
+		JumpList:              []JumpAction{{Title: "跳转"}},
+		CardAction:            &CardAction{Type: CardActionTypeURL, URL: "https://example.com"},
+		CardImage:             &CardImage{URL: "https://example.com/a.png"},
+		ImageTextArea:         &ImageTextArea{ImageURL: "https://example.com/b.png"},
+		VerticalContentList:   []VerticalContent{{Title: "竖"}},
+		ButtonSelection:       &SelectionItem{QuestionKey: "bs", OptionList: []SelectOption{{ID: "a", Text: "A"}, {ID: "b", Text: "B"}}},
+		ButtonList:            []Button{{Text: "同意", Key: "approve"}, {Text: "拒绝", Key: "reject"}},
+		Checkbox:              &Checkbox{QuestionKey: "cb", OptionList: []CheckboxOption{{ID: "x", Text: "X"}, {ID: "y", Text: "Y"}}},
+		SelectList:            []SelectionItem{{QuestionKey: "sl", OptionList: []SelectOption{{ID: "m", Text: "M"}}}},
+		SubmitButton:          &SubmitButton{Text: "提交", Key: "submit"},
+		TaskID:                "task",
+		Feedback:              &Feedback{ID: "fb"},
+	}
+}
+
+// mutateCard 修改卡片中所有通过指针或切片引用的内容。
+func mutateCard(c *TemplateCard) {
+	c.Source.Desc = "changed"
+	c.ActionMenu.ActionList[0].Key = "changed"
+	c.MainTitle.Title = "changed"
+	c.EmphasisContent.Title = "changed"
+	c.QuoteArea.Title = "changed"
+	c.HorizontalContentList[0].Value = "changed"
+	c.JumpList[0].Title = "changed"
+	c.CardAction.URL = "changed"
+	c.CardImage.URL = "changed"
+	c.ImageTextArea.ImageURL = "changed"
+	c.VerticalContentList[0].Title = "changed"
+	c.ButtonSelection.OptionList[0].Text = "changed"
+	c.ButtonList[0].Text = "changed"
+	c.Checkbox.OptionList[0].IsChecked = true
+	c.SelectList[0].OptionList[0].Text = "changed"
+	c.SubmitButton.Text = "changed"
+	c.Feedback.ID = "changed"
+}
+
+func TestTemplateCardClone(t *testing.T) {
+	orig := fullCard()
+	clone := orig.Clone()
+	if !reflect.DeepEqual(orig, clone) {
+		t.Fatal("clone differs from original")
+	}
+	mutateCard(clone)
+	if !reflect.DeepEqual(orig, fullCard()) {
+		t.Error("modifying the clone changed the original")
+	}
+	if (*TemplateCard)(nil).Clone() != nil {
+		t.Error("nil Clone returned a card")
+	}
+}
+
+func TestUpdatedCard(t *testing.T) {
+	var ev TemplateCardEvent
+	err := json.Unmarshal([]byte(`{"card_type":"button_interaction","event_key":"approve","task_id":"new_task",
+		"selected_items":{"selected_item":[
+			{"question_key":"bs","option_ids":{"option_id":["b"]}},
+			{"question_key":"cb","option_ids":{"option_id":["y"]}},
+			{"question_key":"sl","option_ids":{"option_id":["m"]}}]}}`), &ev)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	orig := fullCard()
+	card := UpdatedCard(orig, &ev)
+	if !reflect.DeepEqual(orig, fullCard()) {
+		t.Fatal("UpdatedCard modified the original card")
+	}
+	if card.TaskID != "new_task" {
+		t.Errorf("TaskID = %q", card.TaskID)
+	}
+	if s := card.ButtonSelection; s.SelectedID != "b" || !s.Disable {
+		t.Errorf("button_selection = %+v", s)
+	}
+	if s := card.SelectList[0]; s.SelectedID != "m" || !s.Disable {
+		t.Errorf("select_list[0] = %+v", s)
+	}
+	if cb := card.Checkbox; cb.OptionList[0].IsChecked || !cb.OptionList[1].IsChecked || !cb.Disable {
+		t.Errorf("checkbox = %+v", cb)
+	}
+	if card.ButtonList[0].Text != DefaultSubmittedText || card.ButtonList[1].Text != "拒绝" {
+		t.Errorf("buttons = %+v", card.ButtonList)
+	}
+	mutateCard(card)
+	if !reflect.DeepEqual(orig, fullCard()) {
+		t.Error("modifying the updated card changed the original")
+	}
+
+	kept := UpdatedCard(fullCard(), &ev, WithKeepEnabled(), WithSubmittedText(""), WithCardHook(func(c *TemplateCard, ev *TemplateCardEvent) {
+		c.SubTitleText = "hook " + ev.EventKey
+	}))
+	if kept.ButtonSelection.Disable || kept.Checkbox.Disable || kept.ButtonList[0].Text != "同意" || kept.SubTitleText != "hook approve" {
+		t.Errorf("options not applied: %+v", kept)
+	}
+}
+
+func TestUpdatedCardNil(t *testing.T) {
+	if UpdatedCard(nil, &TemplateCardEvent{TaskID: "t"}) != nil {
+		t.Error("nil card produced a card")
+	}
+	orig := fullCard()
+	card := UpdatedCard(orig, nil)
+	if !reflect.DeepEqual(card, orig) || card == orig {
+		t.Errorf("nil event = %+v, want an unmodified copy", card)
+	}
+}
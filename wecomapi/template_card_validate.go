@@ -0,0 +1,315 @@
+package wecomapi
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// 模板卡片的平台限制。
+const (
+	maxKeyBytes            = 1024
+	maxOptionIDBytes       = 128
+	maxTaskIDBytes         = 128
+	maxFeedbackIDBytes     = 256
+	maxQuestionBytes       = 200
+	maxActionItems         = 3
+	maxHorizontalItems     = 6
+	maxJumpItems           = 3
+	maxVerticalItems       = 4
+	maxButtonItems         = 6
+	maxSelectItems         = 3
+	maxSelectOptions       = 10
+	maxCheckboxOptions     = 20
+	minCardImageAspect     = 1.3
+	maxCardImageAspect     = 2.25
+	strictSourceDesc       = 13
+	strictMainTitle        = 26
+	strictMainDesc         = 30
+	strictEmphasisTitle    = 10
+	strictEmphasisDesc     = 15
+	strictSubTitle         = 112
+	strictHorizontalKey    = 5
+	strictHorizontalValue  = 26
+	strictJumpTitle        = 13
+	strictVerticalTitle    = 26
+	strictVerticalDesc     = 112
+	strictSelectionTitle   = 13
+	strictSelectOptionText = 10
+	strictCheckboxText     = 11
+	strictButtonText       = 10
+)
+
+// Validate 检查卡片是否满足文档中的必填项和平台限制，返回 ValidationErrors。
+func (c *TemplateCard) Validate() error {
+	v := &cardValidator{}
+	v.card(c)
+	return v.err()
+}
+
+// ValidateStrict 在 Validate 的基础上，额外检查文档建议的字数限制。
+func (c *TemplateCard) ValidateStrict() error {
+	v := &cardValidator{strict: true}
+	v.card(c)
+	return v.err()
+}
+
+// cardValidator 模板卡片校验器。
+type cardValidator struct {
+	validator
+	strict bool
+	keys   map[string]bool // 卡片内菜单项、按钮和提交按钮共用的key集合
+}
+
+// maxBytes 检查字段的字节长度上限。
+func (v *cardValidator) maxBytes(field, s string, n int) {
+	if len(s) > n {
+		v.add(field, "exceeds %d bytes (got %d)", n, len(s))
+	}
+}
+
+// maxChars 严格模式下检查建议的字数上限。
+func (v *cardValidator) maxChars(field, s string, n int) {
+	if v.strict && utf8.RuneCountInString(s) > n {
+		v.add(field, "exceeds recommended %d characters (got %d)", n, utf8.RuneCountInString(s))
+	}
+}
+
+// count 检查列表长度范围。
+func (v *cardValidator) count(field string, n, lo, hi int) {
+	if n < lo || n > hi {
+		v.add(field, "must have %d-%d items (got %d)", lo, hi, n)
+	}
+}
+
+// key 检查key的长度和在seen中的唯一性。
+func (v *cardValidator) key(field, key string, seen map[string]bool) {
+	if key == "" {
+		v.add(field, "is required")
+		return
+	}
+	v.maxBytes(field, key, maxKeyBytes)
+	if seen[key] {
+		v.add(field, "duplicate key %q", key)
+	}
+	seen[key] = true
+}
+
+func (v *cardValidator) card(c *TemplateCard) {
+	v.keys = make(map[string]bool)
+	v.requiredByType(c)
+	if c.Source != nil {
+		v.maxChars("source.desc", c.Source.Desc, strictSourceDesc)
+	}
+	if c.ActionMenu != nil {
+		v.actionMenu(c.ActionMenu)
+	}
+	if c.MainTitle != nil {
+		v.maxChars("main_title.title", c.MainTitle.Title, strictMainTitle)
+		v.maxChars("main_title.desc", c.MainTitle.Desc, strictMainDesc)
+	}
+	if c.EmphasisContent != nil {
+		v.maxChars("emphasis_content.title", c.EmphasisContent.Title, strictEmphasisTitle)
+		v.maxChars("emphasis_content.desc", c.EmphasisContent.Desc, strictEmphasisDesc)
+	}
+	if q := c.QuoteArea; q != nil {
+		v.link("quote_area", int(q.Type), q.URL, q.AppID)
+	}
+	v.maxChars("sub_title_text", c.SubTitleText, strictSubTitle)
+	v.count("horizontal_content_list", len(c.HorizontalContentList), 0, maxHorizontalItems)
+	for i, h := range c.HorizontalContentList {
+		v.horizontal(fmt.Sprintf("horizontal_content_list[%d]", i), h)
+	}
+	v.count("jump_list", len(c.JumpList), 0, maxJumpItems)
+	for i, j := range c.JumpList {
+		v.jump(fmt.Sprintf("jump_list[%d]", i), j)
+	}
+	if a := c.CardAction; a != nil {
+		v.link("card_action", int(a.Type), a.URL, a.AppID)
+		if c.CardType == TemplateCardTypeTextNotice && a.Type != CardActionTypeURL && a.Type != CardActionTypeApplet {
+			v.add("card_action.type", "must be 1 or 2 for %s", c.CardType)
+		}
+	}
+	if img := c.CardImage; img != nil {
+		v.required("card_image.url", img.URL != "")
+		if img.AspectRatio != 0 && (img.AspectRatio < minCardImageAspect || img.AspectRatio > maxCardImageAspect) {
+			v.add("card_image.aspect_ratio", "must be between %.2f and %.2f (got %g)", minCardImageAspect, maxCardImageAspect, img.AspectRatio)
+		}
+	}
+	if a := c.ImageTextArea; a != nil {
+		v.link("image_text_area", int(a.Type), a.URL, a.AppID)
+		v.required("image_text_area.image_url", a.ImageURL != "")
+	}
+	v.count("vertical_content_list", len(c.VerticalContentList), 0, maxVerticalItems)
+	for i, vc := range c.VerticalContentList {
+		path := fmt.Sprintf("vertical_content_list[%d]", i)
+		v.required(path+".title", vc.Title != "")
+		v.maxChars(path+".title", vc.Title, strictVerticalTitle)
+		v.maxChars(path+".desc", vc.Desc, strictVerticalDesc)
+	}
+	questionKeys := make(map[string]bool)
+	if c.ButtonSelection != nil {
+		v.selection("button_selection", c.ButtonSelection, questionKeys)
+	}
+	v.count("button_list", len(c.ButtonList), 0, maxButtonItems)
+	for i, b := range c.ButtonList {
+		path := fmt.Sprintf("button_list[%d]", i)
+		v.required(path+".text", b.Text != "")
+		v.maxChars(path+".text", b.Text, strictButtonText)
+		v.key(path+".key", b.Key, v.keys)
+	}
+	if c.Checkbox != nil {
+		v.checkbox(c.Checkbox, questionKeys)
+	}
+	v.count("select_list", len(c.SelectList), 0, maxSelectItems)
+	for i := range c.SelectList {
+		v.selection(fmt.Sprintf("select_list[%d]", i), &c.SelectList[i], questionKeys)
+	}
+	if s := c.SubmitButton; s != nil {
+		v.required("submit_button.text", s.Text != "")
+		v.maxChars("submit_button.text", s.Text, strictButtonText)
+		v.key("submit_button.key", s.Key, v.keys)
+	}
+	if c.TaskID != "" {
+		v.taskID(c.TaskID)
+	}
+	if c.Feedback != nil {
+		v.maxBytes("feedback.id", c.Feedback.ID, maxFeedbackIDBytes)
+	}
+}
+
+// requiredByType 检查各卡片类型的必填元素。
+func (v *cardValidator) requiredByType(c *TemplateCard) {
+	switch c.CardType {
+	case TemplateCardTypeTextNotice:
+		v.required("main_title.title|sub_title_text", (c.MainTitle != nil && c.MainTitle.Title != "") || c.SubTitleText != "")
+		v.required("card_action", c.CardAction != nil)
+	case TemplateCardTypeNewsNotice:
+		v.required("main_title", c.MainTitle != nil)
+		v.required("card_image|image_text_area", c.CardImage != nil || c.ImageTextArea != nil)
+		v.required("card_action", c.CardAction != nil)
+	case TemplateCardTypeButtonInteraction:
+		v.required("main_title", c.MainTitle != nil)
+		v.required("button_list", len(c.ButtonList) > 0)
+		v.required("task_id", c.TaskID != "")
+	case TemplateCardTypeVoteInteraction:
+		v.required("main_title", c.MainTitle != nil)
+		v.required("checkbox", c.Checkbox != nil)
+		v.required("submit_button", c.SubmitButton != nil)
+		v.required("task_id", c.TaskID != "")
+	case TemplateCardTypeMultipleInteraction:
+		v.required("main_title", c.MainTitle != nil)
+		v.required("select_list", len(c.SelectList) > 0)
+		v.required("submit_button", c.SubmitButton != nil)
+	case "":
+		v.add("card_type", "is required")
+	default:
+		v.add("card_type", "unknown card type %q", c.CardType)
+	}
+	if c.ActionMenu != nil {
+		v.required("task_id", c.TaskID != "")
+	}
+}
+
+// link 检查跳转类字段：type=1需要url，type=2需要appid。
+func (v *cardValidator) link(path string, typ int, url, appID string) {
+	switch typ {
+	case 0:
+	case 1:
+		v.required(path+".url", url != "")
+	case 2:
+		v.required(path+".appid", appID != "")
+	default:
+		v.add(path+".type", "unknown type %d", typ)
+	}
+}
+
+func (v *cardValidator) actionMenu(m *ActionMenu) {
+	v.required("action_menu.desc", m.Desc != "")
+	v.count("action_menu.action_list", len(m.ActionList), 1, maxActionItems)
+	for i, item := range m.ActionList {
+		path := fmt.Sprintf("action_menu.action_list[%d]", i)
+		v.required(path+".text", item.Text != "")
+		v.key(path+".key", item.Key, v.keys)
+	}
+}
+
+func (v *cardValidator) horizontal(path string, h HorizontalContent) {
+	v.required(path+".keyname", h.KeyName != "")
+	v.maxChars(path+".keyname", h.KeyName, strictHorizontalKey)
+	v.maxChars(path+".value", h.Value, strictHorizontalValue)
+	switch h.Type {
+	case HorizontalContentTypeText:
+	case HorizontalContentTypeURL:
+		v.required(path+".url", h.URL != "")
+	case HorizontalContentTypeUserID:
+		v.required(path+".userid", h.UserID != "")
+	default:
+		v.add(path+".type", "unknown type %d", h.Type)
+	}
+}
+
+func (v *cardValidator) jump(path string, j JumpAction) {
+	v.required(path+".title", j.Title != "")
+	v.maxChars(path+".title", j.Title, strictJumpTitle)
+	if j.Type == JumpActionTypeQuestion {
+		v.required(path+".question", j.Question != "")
+		v.maxBytes(path+".question", j.Question, maxQuestionBytes)
+		return
+	}
+	v.link(path, int(j.Type), j.URL, j.AppID)
+}
+
+func (v *cardValidator) selection(path string, s *SelectionItem, questionKeys map[string]bool) {
+	v.key(path+".question_key", s.QuestionKey, questionKeys)
+	v.maxChars(path+".title", s.Title, strictSelectionTitle)
+	v.count(path+".option_list", len(s.OptionList), 1, maxSelectOptions)
+	ids := make(map[string]bool)
+	for i, o := range s.OptionList {
+		optPath := fmt.Sprintf("%s.option_list[%d]", path, i)
+		v.optionID(optPath+".id", o.ID, ids)
+		v.required(optPath+".text", o.Text != "")
+		v.maxChars(optPath+".text", o.Text, strictSelectOptionText)
+	}
+}
+
+func (v *cardValidator) checkbox(c *Checkbox, questionKeys map[string]bool) {
+	v.key("checkbox.question_key", c.QuestionKey, questionKeys)
+	if c.Mode != CheckboxModeSingle && c.Mode != CheckboxModeMulti {
+		v.add("checkbox.mode", "unknown mode %d", c.Mode)
+	}
+	v.count("checkbox.option_list", len(c.OptionList), 1, maxCheckboxOptions)
+	ids := make(map[string]bool)
+	for i, o := range c.OptionList {
+		optPath := fmt.Sprintf("checkbox.option_list[%d]", i)
+		v.optionID(optPath+".id", o.ID, ids)
+		v.required(optPath+".text", o.Text != "")
+		v.maxChars(optPath+".text", o.Text, strictCheckboxText)
+	}
+}
+
+func (v *cardValidator) optionID(field, id string, seen map[string]bool) {
+	if id == "" {
+		v.add(field, "is required")
+		return
+	}
+	v.maxBytes(field, id, maxOptionIDBytes)
+	if seen[id] {
+		v.add(field, "duplicate option id %q", id)
+	}
+	seen[id] = true
+}
+
+// taskID 检查任务ID：最长128字节，只能由数字、字母和“_-@”组成。
+func (v *cardValidator) taskID(id string) {
+	v.maxBytes("task_id", id, maxTaskIDBytes)
+	for _, r := range id {
+		if !isTaskIDRune(r) {
+			v.add("task_id", "invalid character %q", r)
+			return
+		}
+	}
+}
+
+func isTaskIDRune(r rune) bool {
+	return r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '_' || r == '-' || r == '@'
+}
@@ -0,0 +1,191 @@
+package wecomapi
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// validButtonCard 返回一张合法的按钮交互卡片，用于逐项修改后校验。
+func validButtonCard() *TemplateCard {
+	return &TemplateCard{
+		CardType:   TemplateCardTypeButtonInteraction,
+		MainTitle:  &MainTitle{Title: "审批"},
+		TaskID:     "task_1",
+		ButtonList: []Button{{Text: "同意", Key: "approve"}, {Text: "拒绝", Key: "reject"}},
+	}
+}
+
+func TestTemplateCardValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(c *TemplateCard)
+		want   []string
+	}{
+		{"valid", func(c *TemplateCard) {}, nil},
+		{"missing card_type", func(c *TemplateCard) { c.CardType = "" }, []string{"card_type"}},
+		{"unknown card_type", func(c *TemplateCard) { c.CardType = "poster" }, []string{"card_type"}},
+		{"task_id charset", func(c *TemplateCard) { c.TaskID = "task 1" }, []string{"task_id"}},
+		{"task_id length", func(c *TemplateCard) { c.TaskID = strings.Repeat("a", maxTaskIDBytes+1) }, []string{"task_id"}},
+		{"task_id allowed symbols", func(c *TemplateCard) { c.TaskID = "a_b-c@d" }, nil},
+		{"feedback id length", func(c *TemplateCard) { c.Feedback = &Feedback{ID: strings.Repeat("f", maxFeedbackIDBytes+1)} }, []string{"feedback.id"}},
+		{"button text required", func(c *TemplateCard) { c.ButtonList[0].Text = "" }, []string{"button_list[0].text"}},
+		{"button key required", func(c *TemplateCard) { c.ButtonList[0].Key = "" }, []string{"button_list[0].key"}},
+		{"button key length", func(c *TemplateCard) { c.ButtonList[0].Key = strings.Repeat("k", maxKeyBytes+1) }, []string{"button_list[0].key"}},
+		{"duplicate button key", func(c *TemplateCard) { c.ButtonList[1].Key = "approve" }, []string{"button_list[1].key"}},
+		{
+			"too many buttons",
+			func(c *TemplateCard) {
+				for i := range maxButtonItems {
+					c.ButtonList = append(c.ButtonList, Button{Text: "b", Key: "k" + string(rune('a'+i))})
+				}
+			},
+			[]string{"button_list"},
+		},
+		{
+			"action_menu key shared with button",
+			func(c *TemplateCard) {
+				c.ActionMenu = &ActionMenu{Desc: "更多", ActionList: []ActionItem{{Text: "同意", Key: "approve"}}}
+			},
+			[]string{"button_list[0].key"},
+		},
+		{
+			"action_menu needs items",
+			func(c *TemplateCard) { c.ActionMenu = &ActionMenu{Desc: "更多"} },
+			[]string{"action_menu.action_list"},
+		},
+		{
+			"action_menu needs task_id",
+			func(c *TemplateCard) {
+				c.CardType = TemplateCardTypeTextNotice
+				c.TaskID = ""
+				c.ButtonList = nil
+				c.CardAction = CardActionURL("https://example.com")
+				c.ActionMenu = &ActionMenu{Desc: "更多", ActionList: []ActionItem{{Text: "x", Key: "x"}}}
+			},
+			[]string{"task_id"},
+		},
+		{
+			"duplicate question_key",
+			func(c *TemplateCard) {
+				c.ButtonSelection = &SelectionItem{QuestionKey: "q", OptionList: []SelectOption{{ID: "a", Text: "A"}}}
+				c.SelectList = []SelectionItem{{QuestionKey: "q", OptionList: []SelectOption{{ID: "a", Text: "A"}}}}
+			},
+			[]string{"select_list[0].question_key"},
+		},
+		{
+			"duplicate option id",
+			func(c *TemplateCard) {
+				c.ButtonSelection = &SelectionItem{QuestionKey: "q", OptionList: []SelectOption{{ID: "a", Text: "A"}, {ID: "a", Text: "B"}}}
+			},
+			[]string{"button_selection.option_list[1].id"},
+		},
+		{
+			"option id length",
+			func(c *TemplateCard) {
+				c.ButtonSelection = &SelectionItem{QuestionKey: "q", OptionList: []SelectOption{{ID: strings.Repeat("o", maxOptionIDBytes+1), Text: "A"}}}
+			},
+			[]string{"button_selection.option_list[0].id"},
+		},
+		{
+			"horizontal url required",
+			func(c *TemplateCard) {
+				c.HorizontalContentList = []HorizontalContent{{Type: HorizontalContentTypeURL, KeyName: "链接"}}
+			},
+			[]string{"horizontal_content_list[0].url"},
+		},
+		{
+			"horizontal userid required",
+			func(c *TemplateCard) {
+				c.HorizontalContentList = []HorizontalContent{{Type: HorizontalContentTypeUserID, KeyName: "成员"}}
+			},
+			[]string{"horizontal_content_list[0].userid"},
+		},
+		{
+			"jump question length",
+			func(c *TemplateCard) {
+				c.JumpList = []JumpAction{JumpQuestion("问", strings.Repeat("q", maxQuestionBytes+1))}
+			},
+			[]string{"jump_list[0].question"},
+		},
+		{
+			"jump applet needs appid",
+			func(c *TemplateCard) { c.JumpList = []JumpAction{{Type: JumpActionTypeApplet, Title: "小程序"}} },
+			[]string{"jump_list[0].appid"},
+		},
+		{
+			"quote_area url required",
+			func(c *TemplateCard) { c.QuoteArea = &QuoteArea{Type: 1} },
+			[]string{"quote_area.url"},
+		},
+		{
+			"card image aspect ratio",
+			func(c *TemplateCard) { c.CardImage = &CardImage{URL: "https://example.com/a.png", AspectRatio: 3} },
+			[]string{"card_image.aspect_ratio"},
+		},
+		{
+			"vertical title required",
+			func(c *TemplateCard) { c.VerticalContentList = []VerticalContent{{Desc: "内容"}} },
+			[]string{"vertical_content_list[0].title"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := validButtonCard()
+			tt.modify(c)
+			if got := errorFields(t, c.Validate()); !slices.Equal(got, tt.want) {
+				t.Errorf("fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplateCardValidateSharedKeys(t *testing.T) {
+	tests := []struct {
+		name string
+		card *TemplateCard
+		want []string
+	}{
+		{
+			name: "submit_button key shared with action_menu",
+			card: &TemplateCard{
+				CardType:     TemplateCardTypeVoteInteraction,
+				MainTitle:    &MainTitle{Title: "投票"},
+				TaskID:       "vote",
+				ActionMenu:   &ActionMenu{Desc: "更多", ActionList: []ActionItem{{Text: "提交", Key: "submit"}}},
+				Checkbox:     &Checkbox{QuestionKey: "q", Mode: CheckboxModeSingle, OptionList: []CheckboxOption{{ID: "a", Text: "A"}}},
+				SubmitButton: &SubmitButton{Text: "提交", Key: "submit"},
+			},
+			want: []string{"submit_button.key"},
+		},
+		{
+			name: "question_key may equal a button key",
+			card: &TemplateCard{
+				CardType:     TemplateCardTypeMultipleInteraction,
+				MainTitle:    &MainTitle{Title: "选择"},
+				SelectList:   []SelectionItem{{QuestionKey: "submit", OptionList: []SelectOption{{ID: "a", Text: "A"}}}},
+				SubmitButton: &SubmitButton{Text: "提交", Key: "submit"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorFields(t, tt.card.Validate()); !slices.Equal(got, tt.want) {
+				t.Errorf("fields = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTemplateCardValidateStrict(t *testing.T) {
+	c := validButtonCard()
+	c.MainTitle.Title = strings.Repeat("标", strictMainTitle+1)
+	c.ButtonList[0].Text = strings.Repeat("按", strictButtonText+1)
+	if err := c.Validate(); err != nil {
+		t.Fatalf("Validate enforced recommended lengths: %v", err)
+	}
+	want := []string{"main_title.title", "button_list[0].text"}
+	if got := errorFields(t, c.ValidateStrict()); !slices.Equal(got, want) {
+		t.Errorf("strict fields = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,185 @@
+package wecomapi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FieldError 单个字段的校验错误。
+type FieldError struct {
+	Field  string // 字段路径，如 event.template_card_event.task_id
+	Reason string // 错误原因
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Reason
+}
+
+// ValidationErrors 校验发现的全部字段错误。
+type ValidationErrors []*FieldError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Error()
+	}
+	return "wecomapi: invalid fields: " + strings.Join(msgs, "; ")
+}
+
+// validator 收集字段错误。
+type validator struct {
+	errs ValidationErrors
+}
+
+func (v *validator) add(field, format string, args ...any) {
+	v.errs = append(v.errs, &FieldError{Field: field, Reason: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) required(field string, ok bool) {
+	if !ok {
+		v.add(field, "is required")
+	}
+}
+
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+func joinPath(prefix, field string) string {
+	if prefix == "" {
+		return field
+	}
+	return prefix + "." + field
+}
+
+// Validate 按消息类型检查回调的必填字段，返回 ValidationErrors。
+func (c *Callback) Validate() error {
+	v := &validator{}
+	c.validate(v)
+	return v.err()
+}
+
+func (c *Callback) validate(v *validator) {
+	v.required("msgid", c.MsgID != "")
+	v.required("aibotid", c.AIBotID != "")
+	v.required("from.userid", c.From.UserID != "")
+	switch c.ChatType {
+	case ChatTypeSingle, ChatTypeGroup:
+	case "":
+		// 进入会话事件只在单聊触发，协议中不带chattype
+		if !c.isEnterChat() {
+			v.add("chattype", "is required")
+		}
+	default:
+		v.add("chattype", "unknown chat type %q", c.ChatType)
+	}
+	switch c.MsgType {
+	case "":
+		v.add("msgtype", "is required")
+	case CallbackMsgTypeText:
+		v.required("text", c.Text != nil)
+	case CallbackMsgTypeImage:
+		v.required("image", c.Image != nil)
+		if c.Image != nil {
+			v.required("image.url", c.Image.URL != "")
+		}
+	case CallbackMsgTypeMixed:
+		validateMixed(v, "mixed", c.Mixed)
+	case CallbackMsgTypeVoice:
+		v.required("voice", c.Voice != nil)
+	case CallbackMsgTypeFile:
+		v.required("file", c.File != nil)
+		if c.File != nil {
+			v.required("file.url", c.File.URL != "")
+		}
+	case CallbackMsgTypeStream:
+		v.required("stream", c.Stream != nil)
+		if c.Stream != nil {
+			v.required("stream.id", c.Stream.ID != "")
+		}
+	case CallbackMsgTypeEvent:
+		v.required("event", c.Event != nil)
+		if c.Event != nil {
+			c.Event.validate(v, "event")
+		}
+	}
+	if c.Quote != nil {
+		c.Quote.validate(v, "quote")
+	}
+}
+
+// isEnterChat 判断是否为进入会话事件。
+func (c *Callback) isEnterChat() bool {
+	return c.MsgType == CallbackMsgTypeEvent && c.Event != nil && c.Event.EventType == EventTypeEnterChat
+}
+
+func validateMixed(v *validator, path string, m *Mixed) {
+	v.required(path, m != nil)
+	if m == nil {
+		return
+	}
+	v.required(path+".msg_item", len(m.MsgItem) > 0)
+	for i, item := range m.MsgItem {
+		itemPath := fmt.Sprintf("%s.msg_item[%d]", path, i)
+		switch item.MsgType {
+		case MsgItemTypeText:
+			v.required(itemPath+".text", item.Text != nil)
+		case MsgItemTypeImage:
+			v.required(itemPath+".image", item.Image != nil)
+		default:
+			v.add(itemPath+".msgtype", "unknown item type %q", item.MsgType)
+		}
+	}
+}
+
+func (q *Quote) validate(v *validator, path string) {
+	switch q.MsgType {
+	case QuoteMsgTypeText:
+		v.required(path+".text", q.Text != nil)
+	case QuoteMsgTypeImage:
+		v.required(path+".image", q.Image != nil)
+	case QuoteMsgTypeMixed:
+		validateMixed(v, path+".mixed", q.Mixed)
+	case QuoteMsgTypeVoice:
+		v.required(path+".voice", q.Voice != nil)
+	case QuoteMsgTypeFile:
+		v.required(path+".file", q.File != nil)
+	case "":
+		v.add(path+".msgtype", "is required")
+	default:
+		v.add(path+".msgtype", "unknown quote type %q", q.MsgType)
+	}
+}
+
+// Validate 按事件类型检查事件的必填字段，返回 ValidationErrors。
+func (e *Event) Validate() error {
+	v := &validator{}
+	e.validate(v, "")
+	return v.err()
+}
+
+func (e *Event) validate(v *validator, prefix string) {
+	switch e.EventType {
+	case "":
+		v.add(joinPath(prefix, "eventtype"), "is required")
+	case EventTypeTemplateCard:
+		path := joinPath(prefix, string(EventTypeTemplateCard))
+		v.required(path, e.TemplateCardEvent != nil)
+		if e.TemplateCardEvent != nil {
+			v.required(path+".card_type", e.TemplateCardEvent.CardType != "")
+			v.required(path+".task_id", e.TemplateCardEvent.TaskID != "")
+		}
+	case EventTypeFeedback:
+		path := joinPath(prefix, string(EventTypeFeedback))
+		v.required(path, e.FeedbackEvent != nil)
+		if fe := e.FeedbackEvent; fe != nil {
+			v.required(path+".id", fe.ID != "")
+			if fe.Type < FeedbackTypeAccurate || fe.Type > FeedbackTypeCancel {
+				v.add(path+".type", "unknown feedback type %d", fe.Type)
+			}
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package wecomapi
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var codeBlock = regexp.MustCompile("(?s)```(?:javascript|json)\n(.*?)```")
+
+// apiSamples 返回API.md中所有回调示例，键为所在行号。
+func apiSamples(t *testing.T) map[int]string {
+	t.Helper()
+	doc, err := os.ReadFile("../API.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	samples := make(map[int]string)
+	for _, loc := range codeBlock.FindAllSubmatchIndex(doc, -1) {
+		body := string(doc[loc[2]:loc[3]])
+		if !strings.Contains(body, `"msgid"`) {
+			continue
+		}
+		line := strings.Count(string(doc[:loc[0]]), "\n") + 1
+		samples[line] = body
+	}
+	return samples
+}
+
+func TestValidateAPISamples(t *testing.T) {
+	samples := apiSamples(t)
+	if len(samples) == 0 {
+		t.Fatal("no callback samples found in API.md")
+	}
+	for line, body := range samples {
+		var cb Callback
+		if err := json.Unmarshal([]byte(body), &cb); err != nil {
+			t.Errorf("API.md:%d: decode: %v", line, err)
+			continue
+		}
+		if err := cb.Validate(); err != nil {
+			t.Errorf("API.md:%d: %s/%v: %v", line, cb.MsgType, cb.Event != nil, err)
+		}
+	}
+}
+
+func TestValidateChatType(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr string
+	}{
+		{
+			name: "enter_chat without chattype",
+			data: `{"msgid":"1","aibotid":"B","from":{"userid":"U"},"msgtype":"event","event":{"eventtype":"enter_chat"}}`,
+		},
+		{
+			name:    "text without chattype",
+			data:    `{"msgid":"1","aibotid":"B","from":{"userid":"U"},"msgtype":"text","text":{"content":"hi"}}`,
+			wantErr: "chattype",
+		},
+		{
+			name:    "unknown chattype",
+			data:    `{"msgid":"1","aibotid":"B","chattype":"room","from":{"userid":"U"},"msgtype":"text","text":{"content":"hi"}}`,
+			wantErr: "chattype",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cb Callback
+			if err := json.Unmarshal([]byte(tt.data), &cb); err != nil {
+				t.Fatal(err)
+			}
+			err := cb.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() = %v", err)
+				}
+				return
+			}
+			var verrs ValidationErrors
+			if !errors.As(err, &verrs) {
+				t.Fatalf("Validate() = %v, want ValidationErrors", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Validate() = %v, want mention of %q", err, tt.wantErr)
+			}
+		})
+	}
+}
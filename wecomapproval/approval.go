@@ -0,0 +1,125 @@
+// Package wecomapproval 基于按钮交互模板卡片的审批流程。
+package wecomapproval
+
+import (
+	"slices"
+	"time"
+
+	"github.com/go-sphere/wecom-bot-api/wecomapi"
+)
+
+// 审批按钮的key。
+const (
+	KeyApprove = "approval_approve"
+	KeyReject  = "approval_reject"
+	KeyDone    = "approval_done"
+)
+
+// Policy 审批通过策略。
+type Policy int
+
+const (
+	// PolicyAny 任一审批人的决定即为最终结果。
+	PolicyAny Policy = iota
+	// PolicyAll 全部审批人同意才通过，任一人拒绝即驳回。
+	PolicyAll
+)
+
+// Status 审批状态。
+type Status string
+
+const (
+	StatusPending  Status = "pending"
+	StatusApproved Status = "approved"
+	StatusRejected Status = "rejected"
+)
+
+// Decision 单个审批人的决定。
+type Decision struct {
+	UserID    string    `json:"user_id"`
+	Approved  bool      `json:"approved"`
+	DecidedAt time.Time `json:"decided_at"`
+}
+
+// Approval 一个审批单。
+type Approval struct {
+	ID        string                       `json:"id"`                // 审批单ID，同时作为卡片TaskID
+	Title     string                       `json:"title"`             // 卡片标题
+	Desc      string                       `json:"desc,omitempty"`    // 标题辅助信息
+	Requester string                       `json:"requester"`         // 申请人UserID
+	Fields    []wecomapi.HorizontalContent `json:"fields,omitempty"`  // 附加信息，如金额、事由
+	Approvers []string                     `json:"approvers"`         // 审批人UserID
+	Policy    Policy                       `json:"policy"`            // 通过策略
+	Decisions []Decision                   `json:"decisions"`         // 已作出的决定
+	Status    Status                       `json:"status"`            // 当前状态
+	CreatedAt time.Time                    `json:"created_at"`        // 创建时间
+	DoneAt    time.Time                    `json:"done_at,omitzero"`  // 完成时间
+	Source    *wecomapi.Source             `json:"source,omitempty"`  // 卡片来源样式
+	ChatID    string                       `json:"chat_id,omitempty"` // 发送到的群聊
+}
+
+// IsApprover 判断用户是否为审批人。
+func (a *Approval) IsApprover(userID string) bool {
+	return slices.Contains(a.Approvers, userID)
+}
+
+// Decision 返回审批人的决定。
+func (a *Approval) Decision(userID string) (Decision, bool) {
+	i := slices.IndexFunc(a.Decisions, func(d Decision) bool { return d.UserID == userID })
+	if i < 0 {
+		return Decision{}, false
+	}
+	return a.Decisions[i], true
+}
+
+// Done 判断审批是否已完成。
+func (a *Approval) Done() bool {
+	return a.Status == StatusApproved || a.Status == StatusRejected
+}
+
+// Participants 返回需要同步卡片的用户：申请人和全部审批人。
+func (a *Approval) Participants() []string {
+	users := slices.Clone(a.Approvers)
+	if a.Requester != "" && !slices.Contains(users, a.Requester) {
+		users = append(users, a.Requester)
+	}
+	return users
+}
+
+// decide 记录决定并按策略更新状态，返回决定是否被接受。
+func (a *Approval) decide(userID string, approved bool, now time.Time) bool {
+	if a.Done() || !a.IsApprover(userID) {
+		return false
+	}
+	if _, ok := a.Decision(userID); ok {
+		return false
+	}
+	a.Decisions = append(a.Decisions, Decision{UserID: userID, Approved: approved, DecidedAt: now})
+	switch {
+	case a.Policy == PolicyAny:
+		a.Status = statusOf(approved)
+	case !approved:
+		a.Status = StatusRejected
+	case len(a.Decisions) == len(a.Approvers):
+		a.Status = StatusApproved
+	}
+	if a.Done() {
+		a.DoneAt = now
+	}
+	return true
+}
+
+func statusOf(approved bool) Status {
+	if approved {
+		return StatusApproved
+	}
+	return StatusRejected
+}
+
+func (a *Approval) clone() *Approval {
+	c := *a
+	c.Fields = slices.Clone(a.Fields)
+	c.Approvers = slices.Clone(a.Approvers)
+	c.Decisions = slices.Clone(a.Decisions)
+	return &c
+}
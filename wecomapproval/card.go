@@ -0,0 +1,118 @@
+package wecomapproval
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/go-sphere/wecom-bot-api/wecomapi"
+)
+
+// maxHorizontalItems 卡片二级标题+文本列表的最大长度。
+const maxHorizontalItems = 6
+
+// Card 根据审批单当前状态生成按钮交互卡片，loc为显示决定时间的时区。
+func (a *Approval) Card(loc *time.Location) (*wecomapi.TemplateCard, error) {
+	b := wecomapi.NewButtonInteractionCard().
+		MainTitle(a.Title, a.Desc).
+		SubTitle(a.statusText()).
+		TaskID(a.ID)
+	if a.Source != nil {
+		b.Source(a.Source.IconURL, a.Source.Desc, a.Source.DescColor)
+	}
+	b.Horizontal(a.horizontal(loc)...)
+	switch a.Status {
+	case StatusApproved:
+		b.Button("已通过", KeyDone, wecomapi.ButtonStyleGray)
+	case StatusRejected:
+		b.Button("已驳回", KeyDone, wecomapi.ButtonStyleGray)
+	default:
+		b.Button("同意", KeyApprove, wecomapi.ButtonStylePrimary)
+		b.Button("拒绝", KeyReject, wecomapi.ButtonStyleRed)
+	}
+	return b.Build()
+}
+
+func (a *Approval) statusText() string {
+	policy := "任一审批人同意即可"
+	if a.Policy == PolicyAll {
+		policy = "需全部审批人同意"
+	}
+	switch a.Status {
+	case StatusApproved:
+		return "审批已通过"
+	case StatusRejected:
+		return "审批已驳回"
+	}
+	return fmt.Sprintf("待审批（%d/%d），%s", len(a.Decisions), len(a.Approvers), policy)
+}
+
+// horizontal 生成附加信息、申请人和审批人状态，共最多6项。
+// 优先保留申请人和审批人，附加信息只占用剩余位置；审批人超出时已作出决定的排在前面，
+// 合并的只有排在最后的审批人。
+func (a *Approval) horizontal(loc *time.Location) []wecomapi.HorizontalContent {
+	slots := maxHorizontalItems
+	if a.Requester != "" {
+		slots--
+	}
+	fields := a.Fields
+	if free := max(slots-len(a.Approvers), 0); len(fields) > free {
+		fields = fields[:free]
+	}
+	items := make([]wecomapi.HorizontalContent, 0, maxHorizontalItems)
+	items = append(items, fields...)
+	if a.Requester != "" {
+		items = append(items, wecomapi.HorizontalUser("申请人", a.Requester, a.Requester))
+	}
+	if len(a.Approvers) <= slots {
+		for _, userID := range a.Approvers {
+			items = append(items, wecomapi.HorizontalUser("审批人", a.decisionText(userID, loc), userID))
+		}
+		return items
+	}
+	approvers := a.decidedFirst()
+	shown := approvers[:slots-1]
+	for _, userID := range shown {
+		items = append(items, wecomapi.HorizontalUser("审批人", a.decisionText(userID, loc), userID))
+	}
+	rest := approvers[len(shown):]
+	decided := 0
+	for _, userID := range rest {
+		if _, ok := a.Decision(userID); ok {
+			decided++
+		}
+	}
+	summary := fmt.Sprintf("等%d人待审批", len(rest))
+	if decided > 0 {
+		summary = fmt.Sprintf("等%d人，%d人已处理", len(rest), decided)
+	}
+	return append(items, wecomapi.HorizontalText("审批人", summary))
+}
+
+// decidedFirst 返回按决定先后排列、未决定者在后的审批人列表。
+func (a *Approval) decidedFirst() []string {
+	approvers := make([]string, 0, len(a.Approvers))
+	for _, d := range a.Decisions {
+		if a.IsApprover(d.UserID) && !slices.Contains(approvers, d.UserID) {
+			approvers = append(approvers, d.UserID)
+		}
+	}
+	for _, userID := range a.Approvers {
+		if !slices.Contains(approvers, userID) {
+			approvers = append(approvers, userID)
+		}
+	}
+	return approvers
+}
+
+func (a *Approval) decisionText(userID string, loc *time.Location) string {
+	d, ok := a.Decision(userID)
+	if !ok {
+		return userID + " 待审批"
+	}
+	verb := "已拒绝"
+	if d.Approved {
+		verb = "已同意"
+	}
+	return fmt.Sprintf("%s %s %s", userID, verb, d.DecidedAt.In(loc).Format("01-02 15:04"))
+}
@@ -0,0 +1,87 @@
+package wecomapproval
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sphere/wecom-bot-api/wecomapi"
+)
+
+func findApprover(items []wecomapi.HorizontalContent, userID string) (wecomapi.HorizontalContent, bool) {
+	for _, item := range items {
+		if item.KeyName == "审批人" && item.UserID == userID {
+			return item, true
+		}
+	}
+	return wecomapi.HorizontalContent{}, false
+}
+
+func TestHorizontalKeepsDecisions(t *testing.T) {
+	decidedAt := time.Date(2025, 3, 4, 10, 30, 0, 0, time.UTC)
+	a := &Approval{
+		ID:        "a1",
+		Title:     "报销",
+		Requester: "alice",
+		Fields: []wecomapi.HorizontalContent{
+			wecomapi.HorizontalText("金额", "100"),
+			wecomapi.HorizontalText("事由", "差旅"),
+			wecomapi.HorizontalText("部门", "研发"),
+			wecomapi.HorizontalText("项目", "X"),
+		},
+		Approvers: []string{"bob", "carol", "dave"},
+		Decisions: []Decision{{UserID: "dave", Approved: true, DecidedAt: decidedAt}},
+	}
+	items := a.horizontal(time.UTC)
+	if len(items) != maxHorizontalItems {
+		t.Fatalf("got %d items, want %d", len(items), maxHorizontalItems)
+	}
+	for _, userID := range a.Approvers {
+		if _, ok := findApprover(items, userID); !ok {
+			t.Errorf("approver %s missing: %+v", userID, items)
+		}
+	}
+	dave, _ := findApprover(items, "dave")
+	if want := "dave 已同意 03-04 10:30"; dave.Value != want {
+		t.Errorf("dave = %q, want %q", dave.Value, want)
+	}
+	if items[0].KeyName != "金额" || items[1].KeyName != "事由" {
+		t.Errorf("fields not kept in order: %+v", items[:2])
+	}
+}
+
+func TestHorizontalManyApprovers(t *testing.T) {
+	decidedAt := time.Date(2025, 3, 4, 10, 30, 0, 0, time.UTC)
+	approvers := make([]string, 8)
+	for i := range approvers {
+		approvers[i] = fmt.Sprintf("u%d", i)
+	}
+	a := &Approval{
+		ID:        "a2",
+		Requester: "alice",
+		Fields:    []wecomapi.HorizontalContent{wecomapi.HorizontalText("金额", "100")},
+		Approvers: approvers,
+		Decisions: []Decision{
+			{UserID: "u7", Approved: false, DecidedAt: decidedAt},
+			{UserID: "u6", Approved: true, DecidedAt: decidedAt},
+		},
+	}
+	items := a.horizontal(time.UTC)
+	if len(items) != maxHorizontalItems {
+		t.Fatalf("got %d items, want %d", len(items), maxHorizontalItems)
+	}
+	for _, userID := range []string{"u7", "u6"} {
+		item, ok := findApprover(items, userID)
+		if !ok {
+			t.Fatalf("decided approver %s missing: %+v", userID, items)
+		}
+		if !strings.Contains(item.Value, "03-04 10:30") {
+			t.Errorf("%s = %q, want decision time", userID, item.Value)
+		}
+	}
+	last := items[len(items)-1]
+	if last.Value != "等4人待审批" {
+		t.Errorf("summary = %q", last.Value)
+	}
+}
@@ -0,0 +1,128 @@
+package wecomapproval
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-sphere/wecom-bot-api/wecomapi"
+)
+
+// ErrCompleteFailed 审批结果已保存，但完成回调返回了错误。
+var ErrCompleteFailed = errors.New("wecomapproval: complete callback failed")
+
+// CompleteFunc 审批完成时的回调。
+type CompleteFunc func(ctx context.Context, a *Approval) error
+
+// Manager 发起审批并处理审批卡片的按钮事件。
+type Manager struct {
+	store      Store
+	onComplete CompleteFunc
+	onError    func(a *Approval, err error)
+	loc        *time.Location
+	now        func() time.Time
+}
+
+// Option 配置 Manager。
+type Option func(*Manager)
+
+// WithOnComplete 设置审批完成（通过或驳回）时的回调。
+func WithOnComplete(fn CompleteFunc) Option {
+	return func(m *Manager) {
+		m.onComplete = fn
+	}
+}
+
+// WithErrorHandler 设置 HandleEvent 中完成回调失败时的处理函数，此时卡片仍会正常更新。
+func WithErrorHandler(fn func(a *Approval, err error)) Option {
+	return func(m *Manager) {
+		m.onError = fn
+	}
+}
+
+// WithLocation 设置卡片中显示决定时间所用的时区，默认为本地时区。
+func WithLocation(loc *time.Location) Option {
+	return func(m *Manager) {
+		m.loc = loc
+	}
+}
+
+// NewManager 创建审批管理器。
+func NewManager(store Store, opts ...Option) *Manager {
+	m := &Manager{store: store, loc: time.Local, now: time.Now}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Start 保存审批单并返回待发送的审批卡片。
+func (m *Manager) Start(ctx context.Context, a *Approval) (*wecomapi.TemplateCard, error) {
+	if len(a.Approvers) == 0 {
+		return nil, errors.New("wecomapproval: no approvers")
+	}
+	a.Status = StatusPending
+	a.Decisions = nil
+	if a.CreatedAt.IsZero() {
+		a.CreatedAt = m.now()
+	}
+	card, err := a.Card(m.loc)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.store.Create(ctx, a); err != nil {
+		return nil, err
+	}
+	return card, nil
+}
+
+// Decide 记录审批人的决定，非审批人、重复决定或已完成的审批不会改变状态。
+// changed 表示本次决定是否被接受。决定已保存但完成回调失败时，
+// 返回包装了 ErrCompleteFailed 的错误，a 和 changed 仍然有效。
+func (m *Manager) Decide(ctx context.Context, id, userID string, approved bool) (a *Approval, changed bool, err error) {
+	a, err = m.store.Update(ctx, id, func(a *Approval) error {
+		changed = a.decide(userID, approved, m.now())
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if changed && a.Done() && m.onComplete != nil {
+		if err := m.onComplete(ctx, a); err != nil {
+			return a, changed, fmt.Errorf("%w: %s: %w", ErrCompleteFailed, a.ID, err)
+		}
+	}
+	return a, changed, nil
+}
+
+// HandleEvent 处理审批卡片的按钮事件，并为申请人和全部审批人更新卡片，可直接注册到 wecomapi.Router。
+// 完成回调失败不影响卡片更新，错误交给 WithErrorHandler 设置的处理函数。
+func (m *Manager) HandleEvent(ctx context.Context, cb *wecomapi.Callback) (*wecomapi.PassiveReply, error) {
+	if cb.Event == nil || cb.Event.TemplateCardEvent == nil {
+		return nil, errors.New("wecomapproval: callback is not a template card event")
+	}
+	ev := cb.Event.TemplateCardEvent
+	var a *Approval
+	var err error
+	switch ev.EventKey {
+	case KeyApprove, KeyReject:
+		a, _, err = m.Decide(ctx, ev.TaskID, cb.From.UserID, ev.EventKey == KeyApprove)
+		if errors.Is(err, ErrCompleteFailed) {
+			if m.onError != nil {
+				m.onError(a, err)
+			}
+			err = nil
+		}
+	default:
+		a, err = m.store.Load(ctx, ev.TaskID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	card, err := a.Card(m.loc)
+	if err != nil {
+		return nil, err
+	}
+	return wecomapi.NewUpdateTemplateCardReply(a.Participants(), card), nil
+}
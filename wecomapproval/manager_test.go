@@ -0,0 +1,155 @@
+package wecomapproval
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/go-sphere/wecom-bot-api/wecomapi"
+)
+
+func newTestManager(opts ...Option) *Manager {
+	m := NewManager(NewMemoryStore(), append([]Option{WithLocation(time.UTC)}, opts...)...)
+	now := time.Date(2026, 5, 6, 9, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return now }
+	return m
+}
+
+func startApproval(t *testing.T, m *Manager, id string, policy Policy) {
+	t.Helper()
+	a := &Approval{ID: id, Title: "报销", Requester: "alice", Approvers: []string{"bob", "carol"}, Policy: policy}
+	if _, err := m.Start(context.Background(), a); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDecidePolicies(t *testing.T) {
+	type step struct {
+		user     string
+		approved bool
+		changed  bool
+		status   Status
+	}
+	tests := []struct {
+		name   string
+		policy Policy
+		steps  []step
+	}{
+		{"any approve", PolicyAny, []step{
+			{"bob", true, true, StatusApproved},
+			{"carol", false, false, StatusApproved},
+		}},
+		{"any reject", PolicyAny, []step{
+			{"carol", false, true, StatusRejected},
+		}},
+		{"all approve", PolicyAll, []step{
+			{"bob", true, true, StatusPending},
+			{"bob", false, false, StatusPending}, // 重复决定
+			{"carol", true, true, StatusApproved},
+		}},
+		{"all reject", PolicyAll, []step{
+			{"bob", true, true, StatusPending},
+			{"carol", false, true, StatusRejected},
+		}},
+		{"non approver", PolicyAll, []step{
+			{"mallory", true, false, StatusPending},
+			{"alice", true, false, StatusPending},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestManager()
+			startApproval(t, m, "a1", tt.policy)
+			for i, s := range tt.steps {
+				a, changed, err := m.Decide(context.Background(), "a1", s.user, s.approved)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if changed != s.changed || a.Status != s.status {
+					t.Errorf("step %d (%s): changed=%v status=%s, want %v %s", i, s.user, changed, a.Status, s.changed, s.status)
+				}
+			}
+		})
+	}
+}
+
+func TestDecideOnComplete(t *testing.T) {
+	var completed []*Approval
+	fail := errors.New("notify failed")
+	m := newTestManager(WithOnComplete(func(ctx context.Context, a *Approval) error {
+		completed = append(completed, a)
+		return fail
+	}))
+	startApproval(t, m, "a1", PolicyAll)
+
+	if _, _, err := m.Decide(context.Background(), "a1", "bob", true); err != nil || len(completed) != 0 {
+		t.Fatalf("pending decision: err=%v, completed=%d", err, len(completed))
+	}
+	a, changed, err := m.Decide(context.Background(), "a1", "carol", true)
+	if !errors.Is(err, ErrCompleteFailed) || !errors.Is(err, fail) {
+		t.Fatalf("Decide = %v, want ErrCompleteFailed wrapping the callback error", err)
+	}
+	if a == nil || !changed || a.Status != StatusApproved {
+		t.Fatalf("Decide returned %+v, changed=%v", a, changed)
+	}
+	if len(completed) != 1 || completed[0].Status != StatusApproved || completed[0].DoneAt.IsZero() {
+		t.Errorf("onComplete calls = %+v", completed)
+	}
+	if saved, _ := m.store.Load(context.Background(), "a1"); saved.Status != StatusApproved {
+		t.Errorf("decision not saved: %s", saved.Status)
+	}
+
+	if _, changed, err := m.Decide(context.Background(), "a1", "bob", false); err != nil || changed || len(completed) != 1 {
+		t.Errorf("decision after completion: changed=%v err=%v calls=%d", changed, err, len(completed))
+	}
+}
+
+func cardEvent(user, taskID, key string) *wecomapi.Callback {
+	return &wecomapi.Callback{
+		From: wecomapi.From{UserID: user},
+		Event: &wecomapi.Event{
+			EventType:         wecomapi.EventTypeTemplateCard,
+			TemplateCardEvent: &wecomapi.TemplateCardEvent{TaskID: taskID, EventKey: key},
+		},
+	}
+}
+
+func TestHandleEvent(t *testing.T) {
+	var reported []error
+	m := newTestManager(
+		WithOnComplete(func(context.Context, *Approval) error { return errors.New("notify failed") }),
+		WithErrorHandler(func(a *Approval, err error) { reported = append(reported, err) }),
+	)
+	startApproval(t, m, "a1", PolicyAny)
+	ctx := context.Background()
+
+	reply, err := m.HandleEvent(ctx, cardEvent("bob", "a1", KeyReject))
+	if err != nil {
+		t.Fatalf("HandleEvent = %v, want the card despite the callback failure", err)
+	}
+	if reply.ResponseType != wecomapi.ReplyResponseTypeUpdateTemplateCard {
+		t.Fatalf("response_type = %s", reply.ResponseType)
+	}
+	if users := reply.UserIDs; !slices.Contains(users, "alice") || !slices.Contains(users, "bob") || !slices.Contains(users, "carol") {
+		t.Errorf("userids = %v", users)
+	}
+	card := reply.TemplateCard
+	if card.TaskID != "a1" || len(card.ButtonList) != 1 || card.ButtonList[0].Key != KeyDone {
+		t.Errorf("card = %+v", card)
+	}
+	if len(reported) != 1 || !errors.Is(reported[0], ErrCompleteFailed) {
+		t.Errorf("reported errors = %v", reported)
+	}
+
+	if reply, err := m.HandleEvent(ctx, cardEvent("carol", "a1", KeyDone)); err != nil || reply.TemplateCard.ButtonList[0].Key != KeyDone {
+		t.Errorf("done button = %v, %v", reply, err)
+	}
+	if _, err := m.HandleEvent(ctx, cardEvent("bob", "missing", KeyApprove)); err == nil {
+		t.Error("unknown approval accepted")
+	}
+	if _, err := m.HandleEvent(ctx, &wecomapi.Callback{}); err == nil {
+		t.Error("non card event accepted")
+	}
+}
@@ -0,0 +1,67 @@
+package wecomapproval
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrNotFound 审批单不存在。
+var ErrNotFound = errors.New("wecomapproval: approval not found")
+
+// Store 审批单持久化接口。
+type Store interface {
+	// Create 保存新的审批单。
+	Create(ctx context.Context, a *Approval) error
+	// Load 读取审批单，不存在时返回 ErrNotFound。
+	Load(ctx context.Context, id string) (*Approval, error)
+	// Update 原子地读取、修改并保存审批单，fn返回错误时不保存。
+	// 多个审批人可能同时点击，实现需保证同一审批单的更新串行执行。
+	Update(ctx context.Context, id string, fn func(a *Approval) error) (*Approval, error)
+}
+
+// MemoryStore 基于内存的审批单存储。
+type MemoryStore struct {
+	mu        sync.Mutex
+	approvals map[string]*Approval
+}
+
+// NewMemoryStore 创建内存审批单存储。
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{approvals: make(map[string]*Approval)}
+}
+
+// Create 保存新的审批单。
+func (m *MemoryStore) Create(_ context.Context, a *Approval) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.approvals[a.ID] = a.clone()
+	return nil
+}
+
+// Load 读取审批单的副本。
+func (m *MemoryStore) Load(_ context.Context, id string) (*Approval, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	a, ok := m.approvals[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return a.clone(), nil
+}
+
+// Update 在锁内修改审批单。
+func (m *MemoryStore) Update(_ context.Context, id string, fn func(a *Approval) error) (*Approval, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	a, ok := m.approvals[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	updated := a.clone()
+	if err := fn(updated); err != nil {
+		return nil, err
+	}
+	m.approvals[id] = updated
+	return updated.clone(), nil
+}
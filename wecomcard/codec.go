@@ -0,0 +1,216 @@
+package wecomcard
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-sphere/wecom-bot-api/wecomapi"
+)
+
+// 签名令牌的长度限制，与模板卡片文档一致。
+const (
+	MaxTaskIDLen = 128  // task_id 最长128字节
+	MaxKeyLen    = 1024 // 按钮key、event_key 最长1024字节
+)
+
+const (
+	tokenPrefix  = "s1@" // 令牌前缀，仅含task_id允许的字符
+	tokenVersion = 2     // 版本2在头部加入了用途字节
+	nonceSize    = 6
+	macSize      = 16
+	headerSize   = 1 + 1 + 4 + nonceSize // 版本 + 用途 + 过期时间 + 随机数
+)
+
+// 令牌用途，写入签名的头部，使TaskID令牌不能当作按钮key使用，反之亦然。
+const (
+	purposeTaskID byte = 1
+	purposeKey    byte = 2
+)
+
+var (
+	// ErrInvalidToken 令牌格式错误或签名不匹配。
+	ErrInvalidToken = errors.New("wecomcard: invalid token")
+	// ErrTokenExpired 令牌已过期。
+	ErrTokenExpired = errors.New("wecomcard: token expired")
+	// ErrTokenTooLong 载荷过大，编码后超出长度限制。
+	ErrTokenTooLong = errors.New("wecomcard: token too long")
+)
+
+// Codec 将应用载荷编码为带HMAC签名和过期时间的TaskID或按钮key，
+// 使无状态的多实例部署无需共享卡片存储即可验证并还原卡片事件的上下文。
+type Codec struct {
+	secret []byte
+	now    func() time.Time
+}
+
+// NewCodec 使用签名密钥创建编解码器，所有实例需使用相同的密钥。
+func NewCodec(secret []byte) *Codec {
+	return &Codec{secret: secret, now: time.Now}
+}
+
+// EncodeTaskID 将载荷编码为TaskID，ttl为0表示不过期。
+func (c *Codec) EncodeTaskID(payload []byte, ttl time.Duration) (string, error) {
+	return c.encode(payload, ttl, purposeTaskID)
+}
+
+// EncodeKey 将载荷编码为按钮key或提交按钮key，ttl为0表示不过期。
+func (c *Codec) EncodeKey(payload []byte, ttl time.Duration) (string, error) {
+	return c.encode(payload, ttl, purposeKey)
+}
+
+// DecodeTaskID 验证 EncodeTaskID 生成的令牌并返回载荷，按钮key令牌返回 ErrInvalidToken。
+func (c *Codec) DecodeTaskID(token string) ([]byte, error) {
+	return c.decode(token, purposeTaskID)
+}
+
+// DecodeKey 验证 EncodeKey 生成的令牌并返回载荷，TaskID令牌返回 ErrInvalidToken。
+func (c *Codec) DecodeKey(token string) ([]byte, error) {
+	return c.decode(token, purposeKey)
+}
+
+// purposeOf 返回长度限制对应的令牌用途。
+func purposeOf(maxLen int) (byte, error) {
+	switch maxLen {
+	case MaxTaskIDLen:
+		return purposeTaskID, nil
+	case MaxKeyLen:
+		return purposeKey, nil
+	}
+	return 0, fmt.Errorf("wecomcard: maxLen must be MaxTaskIDLen or MaxKeyLen, got %d", maxLen)
+}
+
+func maxLenOf(purpose byte) int {
+	if purpose == purposeTaskID {
+		return MaxTaskIDLen
+	}
+	return MaxKeyLen
+}
+
+// MaxPayload 返回编码后不超过maxLen时载荷的最大字节数。
+func MaxPayload(maxLen int) int {
+	return base64.RawURLEncoding.DecodedLen(maxLen-len(tokenPrefix)) - headerSize - macSize
+}
+
+func (c *Codec) encode(payload []byte, ttl time.Duration, purpose byte) (string, error) {
+	maxLen := maxLenOf(purpose)
+	if len(payload) > MaxPayload(maxLen) {
+		return "", fmt.Errorf("%w: payload %d bytes, limit %d", ErrTokenTooLong, len(payload), MaxPayload(maxLen))
+	}
+	buf := make([]byte, headerSize, headerSize+len(payload)+macSize)
+	buf[0] = tokenVersion
+	buf[1] = purpose
+	if ttl > 0 {
+		binary.BigEndian.PutUint32(buf[2:6], uint32(c.now().Add(ttl).Unix()))
+	}
+	if _, err := rand.Read(buf[6:headerSize]); err != nil {
+		return "", err
+	}
+	buf = append(buf, payload...)
+	buf = append(buf, c.sign(buf)...)
+	return tokenPrefix + base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// decode 验证令牌的签名、用途和有效期并返回载荷。
+func (c *Codec) decode(token string, purpose byte) ([]byte, error) {
+	encoded, ok := strings.CutPrefix(token, tokenPrefix)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	buf, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || len(buf) < headerSize+macSize || buf[0] != tokenVersion {
+		return nil, ErrInvalidToken
+	}
+	body, mac := buf[:len(buf)-macSize], buf[len(buf)-macSize:]
+	if !hmac.Equal(mac, c.sign(body)) || body[1] != purpose {
+		return nil, ErrInvalidToken
+	}
+	if exp := binary.BigEndian.Uint32(body[2:6]); exp != 0 && c.now().Unix() >= int64(exp) {
+		return nil, ErrTokenExpired
+	}
+	return body[headerSize:], nil
+}
+
+func (c *Codec) sign(data []byte) []byte {
+	h := hmac.New(sha256.New, c.secret)
+	h.Write(data)
+	return h.Sum(nil)[:macSize]
+}
+
+// IsToken 判断字符串是否为本编解码器格式的令牌（不验证签名）。
+func IsToken(s string) bool {
+	return strings.HasPrefix(s, tokenPrefix)
+}
+
+// EncodeJSON 将值序列化为JSON后编码为令牌，maxLen为 MaxTaskIDLen 或 MaxKeyLen，
+// 分别对应TaskID和按钮key。
+func EncodeJSON(c *Codec, v any, ttl time.Duration, maxLen int) (string, error) {
+	purpose, err := purposeOf(maxLen)
+	if err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return c.encode(data, ttl, purpose)
+}
+
+// DecodeJSON 验证令牌并将载荷反序列化为T，maxLen须与 EncodeJSON 时一致。
+func DecodeJSON[T any](c *Codec, token string, maxLen int) (*T, error) {
+	purpose, err := purposeOf(maxLen)
+	if err != nil {
+		return nil, err
+	}
+	data, err := c.decode(token, purpose)
+	if err != nil {
+		return nil, err
+	}
+	v := new(T)
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	return v, nil
+}
+
+// Verified 卡片事件中已验证的载荷。
+type Verified struct {
+	Task []byte // TaskID 中的载荷
+	Key  []byte // EventKey 中的载荷，按钮key不是令牌时为nil
+}
+
+// VerifiedHandlerFunc 处理已验证载荷的卡片事件。
+type VerifiedHandlerFunc func(ctx context.Context, cb *wecomapi.Callback, v *Verified) (*wecomapi.PassiveReply, error)
+
+// Handler 返回在分发前验证TaskID和EventKey的事件处理函数，可直接注册到 wecomapi.Router：
+//
+//	router.HandleEvent(wecomapi.EventTypeTemplateCard, codec.Handler(fn))
+//
+// TaskID必须是有效令牌；EventKey仅在带有令牌前缀时验证。
+func (c *Codec) Handler(fn VerifiedHandlerFunc) wecomapi.HandlerFunc {
+	return func(ctx context.Context, cb *wecomapi.Callback) (*wecomapi.PassiveReply, error) {
+		if cb.Event == nil || cb.Event.TemplateCardEvent == nil {
+			return nil, errors.New("wecomcard: callback is not a template card event")
+		}
+		ev := cb.Event.TemplateCardEvent
+		task, err := c.DecodeTaskID(ev.TaskID)
+		if err != nil {
+			return nil, fmt.Errorf("task_id: %w", err)
+		}
+		v := &Verified{Task: task}
+		if IsToken(ev.EventKey) {
+			if v.Key, err = c.DecodeKey(ev.EventKey); err != nil {
+				return nil, fmt.Errorf("event_key: %w", err)
+			}
+		}
+		return fn(ctx, cb, v)
+	}
+}
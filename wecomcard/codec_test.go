@@ -0,0 +1,222 @@
+package wecomcard
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sphere/wecom-bot-api/wecomapi"
+)
+
+func newTestCodec(now time.Time) *Codec {
+	c := NewCodec([]byte("secret"))
+	c.now = func() time.Time { return now }
+	return c
+}
+
+func TestCodecRoundTrip(t *testing.T) {
+	c := newTestCodec(time.Unix(1700000000, 0))
+	payload := []byte(`{"order":42}`)
+	for _, tt := range []struct {
+		name   string
+		encode func([]byte, time.Duration) (string, error)
+		decode func(string) ([]byte, error)
+	}{
+		{"task_id", c.EncodeTaskID, c.DecodeTaskID},
+		{"key", c.EncodeKey, c.DecodeKey},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := tt.encode(payload, time.Hour)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !IsToken(token) {
+				t.Fatalf("IsToken(%q) = false", token)
+			}
+			got, err := tt.decode(token)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, payload) {
+				t.Errorf("Decode = %q, want %q", got, payload)
+			}
+		})
+	}
+}
+
+func TestCodecTampered(t *testing.T) {
+	c := newTestCodec(time.Unix(1700000000, 0))
+	token, err := c.EncodeTaskID([]byte("payload"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(token, tokenPrefix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	flip := func(i int) string {
+		b := bytes.Clone(buf)
+		b[i] ^= 0x01
+		return tokenPrefix + base64.RawURLEncoding.EncodeToString(b)
+	}
+	tests := map[string]string{
+		"mac":        flip(len(buf) - 1),
+		"body":       flip(headerSize),
+		"expiry":     flip(5),
+		"purpose":    flip(1),
+		"version":    flip(0),
+		"prefix":     "s2@" + strings.TrimPrefix(token, tokenPrefix),
+		"truncated":  token[:len(tokenPrefix)+8],
+		"bad base64": tokenPrefix + "!!!",
+	}
+	for name, tok := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := c.DecodeTaskID(tok); !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("Decode = %v, want ErrInvalidToken", err)
+			}
+		})
+	}
+
+	other := NewCodec([]byte("other"))
+	if _, err := other.DecodeTaskID(token); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Decode with other secret = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestCodecExpiry(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	c := newTestCodec(now)
+	token, err := c.EncodeTaskID([]byte("x"), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forever, err := c.EncodeTaskID([]byte("x"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c.now = func() time.Time { return now.Add(time.Minute - time.Second) }
+	if _, err := c.DecodeTaskID(token); err != nil {
+		t.Errorf("before expiry: %v", err)
+	}
+	c.now = func() time.Time { return now.Add(time.Minute) }
+	if _, err := c.DecodeTaskID(token); !errors.Is(err, ErrTokenExpired) {
+		t.Errorf("at expiry: %v, want ErrTokenExpired", err)
+	}
+	c.now = func() time.Time { return now.Add(100 * 365 * 24 * time.Hour) }
+	if _, err := c.DecodeTaskID(forever); err != nil {
+		t.Errorf("zero ttl token: %v", err)
+	}
+}
+
+func TestCodecMaxPayload(t *testing.T) {
+	c := newTestCodec(time.Unix(1700000000, 0))
+	for _, tt := range []struct {
+		name   string
+		maxLen int
+		encode func([]byte, time.Duration) (string, error)
+	}{
+		{"task_id", MaxTaskIDLen, c.EncodeTaskID},
+		{"key", MaxKeyLen, c.EncodeKey},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			n := MaxPayload(tt.maxLen)
+			token, err := tt.encode(bytes.Repeat([]byte{0xff}, n), time.Hour)
+			if err != nil {
+				t.Fatalf("payload of %d bytes: %v", n, err)
+			}
+			if len(token) > tt.maxLen {
+				t.Errorf("token length %d exceeds %d", len(token), tt.maxLen)
+			}
+			if _, err := tt.encode(make([]byte, n+1), time.Hour); !errors.Is(err, ErrTokenTooLong) {
+				t.Errorf("payload of %d bytes: %v, want ErrTokenTooLong", n+1, err)
+			}
+		})
+	}
+}
+
+func TestCodecTaskIDCharset(t *testing.T) {
+	c := newTestCodec(time.Unix(1700000000, 0))
+	for i := range 64 {
+		payload := bytes.Repeat([]byte{byte(i * 4), 0xfb, 0xff}, MaxPayload(MaxTaskIDLen)/3)
+		token, err := c.EncodeTaskID(payload, time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		card := &wecomapi.TemplateCard{
+			CardType:   wecomapi.TemplateCardTypeButtonInteraction,
+			MainTitle:  &wecomapi.MainTitle{Title: "审批"},
+			ButtonList: []wecomapi.Button{{Text: "同意", Key: "ok"}},
+			TaskID:     token,
+		}
+		if err := card.Validate(); err != nil {
+			t.Fatalf("task_id %q: %v", token, err)
+		}
+	}
+}
+
+func TestCodecPurpose(t *testing.T) {
+	c := newTestCodec(time.Unix(1700000000, 0))
+	task, err := c.EncodeTaskID([]byte("p"), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := c.EncodeKey([]byte("p"), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.DecodeKey(task); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("task_id token as key = %v, want ErrInvalidToken", err)
+	}
+	if _, err := c.DecodeTaskID(key); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("key token as task_id = %v, want ErrInvalidToken", err)
+	}
+
+	type order struct{ ID int }
+	tok, err := EncodeJSON(c, order{ID: 7}, 0, MaxKeyLen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := DecodeJSON[order](c, tok, MaxKeyLen); err != nil || got.ID != 7 {
+		t.Errorf("DecodeJSON = %v, %v", got, err)
+	}
+	if _, err := DecodeJSON[order](c, tok, MaxTaskIDLen); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("key JSON token as task_id = %v, want ErrInvalidToken", err)
+	}
+	if _, err := EncodeJSON(c, order{}, 0, 64); err == nil {
+		t.Error("EncodeJSON accepted an unknown maxLen")
+	}
+}
+
+func TestCodecHandler(t *testing.T) {
+	c := newTestCodec(time.Unix(1700000000, 0))
+	task, _ := c.EncodeTaskID([]byte("task"), 0)
+	key, _ := c.EncodeKey([]byte("key"), 0)
+	var got *Verified
+	h := c.Handler(func(ctx context.Context, cb *wecomapi.Callback, v *Verified) (*wecomapi.PassiveReply, error) {
+		got = v
+		return nil, nil
+	})
+	event := func(taskID, eventKey string) *wecomapi.Callback {
+		return &wecomapi.Callback{Event: &wecomapi.Event{
+			EventType:         wecomapi.EventTypeTemplateCard,
+			TemplateCardEvent: &wecomapi.TemplateCardEvent{TaskID: taskID, EventKey: eventKey},
+		}}
+	}
+	if _, err := h(context.Background(), event(task, key)); err != nil {
+		t.Fatal(err)
+	}
+	if string(got.Task) != "task" || string(got.Key) != "key" {
+		t.Errorf("Verified = %q, %q", got.Task, got.Key)
+	}
+	if _, err := h(context.Background(), event(task, "plain")); err != nil || got.Key != nil {
+		t.Errorf("plain key = %v, Key %q", err, got.Key)
+	}
+	if _, err := h(context.Background(), event(key, task)); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("swapped tokens = %v, want ErrInvalidToken", err)
+	}
+}
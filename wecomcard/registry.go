@@ -0,0 +1,176 @@
+package wecomcard
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-sphere/wecom-bot-api/wecomapi"
+)
+
+// DefaultTTL 卡片会话的默认有效期。
+const DefaultTTL = 7 * 24 * time.Hour
+
+// Interaction 一次卡片交互。
+type Interaction struct {
+	Session    *Session                    // 卡片会话
+	Callback   *wecomapi.Callback          // 原始回调
+	Event      *wecomapi.TemplateCardEvent // 模板卡片事件
+	Selections map[string][]string         // 按选择题key分组的选中选项ID
+}
+
+// InteractionFunc 处理卡片交互并返回被动回复，通常为更新卡片的回复。
+type InteractionFunc func(ctx context.Context, in *Interaction) (*wecomapi.PassiveReply, error)
+
+// Registry 按TaskID记录已发送的交互卡片，并将卡片事件分发给对应的回调。
+type Registry struct {
+	store    Store
+	ttl      time.Duration
+	fallback wecomapi.HandlerFunc
+	now      func() time.Time
+
+	mu       sync.RWMutex
+	handlers map[string]InteractionFunc
+}
+
+// Option 配置 Registry。
+type Option func(*Registry)
+
+// WithTTL 设置卡片会话有效期，0表示不过期。
+func WithTTL(ttl time.Duration) Option {
+	return func(r *Registry) {
+		r.ttl = ttl
+	}
+}
+
+// WithFallback 设置会话不存在或已过期时的处理函数。
+func WithFallback(h wecomapi.HandlerFunc) Option {
+	return func(r *Registry) {
+		r.fallback = h
+	}
+}
+
+// NewRegistry 创建卡片会话注册表。
+func NewRegistry(store Store, opts ...Option) *Registry {
+	r := &Registry{
+		store:    store,
+		ttl:      DefaultTTL,
+		now:      time.Now,
+		handlers: make(map[string]InteractionFunc),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Handle 注册交互回调，name 与 Register 时指定的名称对应。
+func (r *Registry) Handle(name string, fn InteractionFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[name] = fn
+}
+
+// RegisterOption 配置 Register 保存的会话。
+type RegisterOption func(*Session)
+
+// WithUserIDs 设置卡片接收者。
+func WithUserIDs(userIDs ...string) RegisterOption {
+	return func(s *Session) {
+		s.UserIDs = userIDs
+	}
+}
+
+// WithChatID 设置卡片所在的群聊会话ID。
+func WithChatID(chatID string) RegisterOption {
+	return func(s *Session) {
+		s.ChatID = chatID
+	}
+}
+
+// WithData 设置随会话保存的应用自定义数据。
+func WithData(data map[string]string) RegisterOption {
+	return func(s *Session) {
+		s.Data = data
+	}
+}
+
+// Register 为卡片分配唯一TaskID并保存会话。传入的卡片不会被修改，
+// 会话保存的是卡片副本，发送时应使用返回的 Session.Card，其中 TaskID 已设置。
+func (r *Registry) Register(ctx context.Context, handler string, card *wecomapi.TemplateCard, opts ...RegisterOption) (*Session, error) {
+	taskID, err := NewTaskID()
+	if err != nil {
+		return nil, err
+	}
+	card = card.Clone()
+	card.TaskID = taskID
+	now := r.now()
+	s := &Session{
+		TaskID:    taskID,
+		Handler:   handler,
+		Card:      card,
+		CreatedAt: now,
+	}
+	if r.ttl > 0 {
+		s.ExpiresAt = now.Add(r.ttl)
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if err := r.store.Save(ctx, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Session 读取卡片会话。
+func (r *Registry) Session(ctx context.Context, taskID string) (*Session, error) {
+	return r.store.Load(ctx, taskID)
+}
+
+// Forget 删除卡片会话，之后该卡片的事件将进入兜底处理。
+func (r *Registry) Forget(ctx context.Context, taskID string) error {
+	return r.store.Delete(ctx, taskID)
+}
+
+// HandleEvent 处理模板卡片事件，可直接注册到 wecomapi.Router：
+//
+//	router.HandleEvent(wecomapi.EventTypeTemplateCard, registry.HandleEvent)
+func (r *Registry) HandleEvent(ctx context.Context, cb *wecomapi.Callback) (*wecomapi.PassiveReply, error) {
+	if cb.Event == nil || cb.Event.TemplateCardEvent == nil {
+		return nil, errors.New("wecomcard: callback is not a template card event")
+	}
+	ev := cb.Event.TemplateCardEvent
+	s, err := r.store.Load(ctx, ev.TaskID)
+	if errors.Is(err, ErrSessionNotFound) && r.fallback != nil {
+		return r.fallback(ctx, cb)
+	}
+	if err != nil {
+		return nil, err
+	}
+	r.mu.RLock()
+	fn, ok := r.handlers[s.Handler]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("wecomcard: no handler %q for task %s", s.Handler, s.TaskID)
+	}
+	return fn(ctx, &Interaction{
+		Session:    s,
+		Callback:   cb,
+		Event:      ev,
+		Selections: ev.Selections(),
+	})
+}
+
+// NewTaskID 生成随机TaskID，由32位十六进制字符组成。
+func NewTaskID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b[:]), nil
+}
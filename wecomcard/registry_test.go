@@ -0,0 +1,189 @@
+package wecomcard
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-sphere/wecom-bot-api/wecomapi"
+)
+
+// recordStore 记录Save时收到的会话副本。
+type recordStore struct {
+	*MemoryStore
+	saved []Session
+}
+
+func (r *recordStore) Save(ctx context.Context, s *Session) error {
+	r.saved = append(r.saved, *s)
+	return r.MemoryStore.Save(ctx, s)
+}
+
+func TestRegisterOptionsReachStore(t *testing.T) {
+	store := &recordStore{MemoryStore: NewMemoryStore()}
+	r := NewRegistry(store)
+	card := &wecomapi.TemplateCard{CardType: wecomapi.TemplateCardTypeButtonInteraction}
+	s, err := r.Register(context.Background(), "approve", card,
+		WithChatID("CHATID"),
+		WithUserIDs("u1", "u2"),
+		WithData(map[string]string{"order": "42"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(store.saved) != 1 {
+		t.Fatalf("Save called %d times, want 1", len(store.saved))
+	}
+	got := store.saved[0]
+	if got.TaskID != s.TaskID || s.Card.TaskID != s.TaskID {
+		t.Errorf("task id mismatch: saved %q, session %q, card %q", got.TaskID, s.TaskID, s.Card.TaskID)
+	}
+	if card.TaskID != "" || s.Card == card {
+		t.Errorf("Register modified or kept the caller's card: %q", card.TaskID)
+	}
+	if got.ChatID != "CHATID" {
+		t.Errorf("ChatID = %q", got.ChatID)
+	}
+	if len(got.UserIDs) != 2 || got.UserIDs[0] != "u1" {
+		t.Errorf("UserIDs = %v", got.UserIDs)
+	}
+	if got.Data["order"] != "42" {
+		t.Errorf("Data = %v", got.Data)
+	}
+	if got.ExpiresAt.IsZero() {
+		t.Error("ExpiresAt not set with default TTL")
+	}
+}
+
+func cardEventCallback(t *testing.T, taskID string) *wecomapi.Callback {
+	t.Helper()
+	data := `{"msgid":"1","aibotid":"B","chattype":"single","from":{"userid":"U"},"msgtype":"event",
+		"event":{"eventtype":"template_card_event","template_card_event":{"card_type":"vote_interaction","event_key":"submit","task_id":"` + taskID + `",
+		"selected_items":{"selected_item":[{"question_key":"q1","option_ids":{"option_id":["a","b"]}}]}}}}`
+	var cb wecomapi.Callback
+	if err := json.Unmarshal([]byte(data), &cb); err != nil {
+		t.Fatal(err)
+	}
+	return &cb
+}
+
+func TestRegistryRouting(t *testing.T) {
+	ctx := context.Background()
+	r := NewRegistry(NewMemoryStore(), WithFallback(func(context.Context, *wecomapi.Callback) (*wecomapi.PassiveReply, error) {
+		return wecomapi.NewTextReply("fallback"), nil
+	}))
+	var got *Interaction
+	r.Handle("vote", func(ctx context.Context, in *Interaction) (*wecomapi.PassiveReply, error) {
+		got = in
+		return wecomapi.NewTextReply("vote"), nil
+	})
+	vote, err := r.Register(ctx, "vote", &wecomapi.TemplateCard{CardType: wecomapi.TemplateCardTypeVoteInteraction}, WithData(map[string]string{"k": "v"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := r.Register(ctx, "missing", &wecomapi.TemplateCard{CardType: wecomapi.TemplateCardTypeButtonInteraction})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reply, err := r.HandleEvent(ctx, cardEventCallback(t, vote.TaskID))
+	if err != nil || reply.Text.Content != "vote" {
+		t.Fatalf("HandleEvent = %v, %v", reply, err)
+	}
+	if got.Session.TaskID != vote.TaskID || got.Session.Data["k"] != "v" || got.Event.EventKey != "submit" {
+		t.Errorf("interaction = %+v", got)
+	}
+	if ids := got.Selections["q1"]; len(ids) != 2 || ids[0] != "a" {
+		t.Errorf("Selections = %v", got.Selections)
+	}
+
+	if reply, err := r.HandleEvent(ctx, cardEventCallback(t, "unknown")); err != nil || reply.Text.Content != "fallback" {
+		t.Errorf("unknown task = %v, %v; want fallback", reply, err)
+	}
+	if _, err := r.HandleEvent(ctx, cardEventCallback(t, other.TaskID)); err == nil {
+		t.Error("session with unregistered handler was dispatched")
+	}
+	if err := r.Forget(ctx, vote.TaskID); err != nil {
+		t.Fatal(err)
+	}
+	if reply, err := r.HandleEvent(ctx, cardEventCallback(t, vote.TaskID)); err != nil || reply.Text.Content != "fallback" {
+		t.Errorf("forgotten task = %v, %v; want fallback", reply, err)
+	}
+	if _, err := r.HandleEvent(ctx, &wecomapi.Callback{MsgType: wecomapi.CallbackMsgTypeText}); err == nil {
+		t.Error("non card event accepted")
+	}
+}
+
+func TestRegistryExpiry(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	store := NewMemoryStore()
+	store.now = clock
+	r := NewRegistry(store, WithTTL(time.Hour))
+	r.now = clock
+	r.Handle("h", func(context.Context, *Interaction) (*wecomapi.PassiveReply, error) { return nil, nil })
+
+	s, err := r.Register(ctx, "h", &wecomapi.TemplateCard{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.ExpiresAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("ExpiresAt = %v", s.ExpiresAt)
+	}
+	now = now.Add(59 * time.Minute)
+	if _, err := r.Session(ctx, s.TaskID); err != nil {
+		t.Fatalf("session expired early: %v", err)
+	}
+	now = now.Add(time.Minute)
+	if _, err := r.Session(ctx, s.TaskID); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("expired session = %v, want ErrSessionNotFound", err)
+	}
+	if _, err := r.HandleEvent(ctx, cardEventCallback(t, s.TaskID)); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("expired event without fallback = %v, want ErrSessionNotFound", err)
+	}
+
+	forever := NewRegistry(store, WithTTL(0))
+	forever.now = clock
+	s, err = forever.Register(ctx, "h", &wecomapi.TemplateCard{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now = now.Add(365 * 24 * time.Hour)
+	if _, err := forever.Session(ctx, s.TaskID); err != nil || !s.ExpiresAt.IsZero() {
+		t.Errorf("session without TTL = %v (expires %v)", err, s.ExpiresAt)
+	}
+}
+
+func TestMemoryStoreCopies(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	s := &Session{
+		TaskID:  "t1",
+		Card:    &wecomapi.TemplateCard{MainTitle: &wecomapi.MainTitle{Title: "原始"}},
+		UserIDs: []string{"u1"},
+		Data:    map[string]string{"k": "v"},
+	}
+	if err := store.Save(ctx, s); err != nil {
+		t.Fatal(err)
+	}
+	s.Card.MainTitle.Title = "调用方修改"
+	s.UserIDs[0] = "u2"
+	s.Data["k"] = "changed"
+
+	loaded, err := store.Load(ctx, "t1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Card.MainTitle.Title != "原始" || loaded.UserIDs[0] != "u1" || loaded.Data["k"] != "v" {
+		t.Errorf("Save kept the caller's session: %+v", loaded)
+	}
+	loaded.Card.MainTitle.Title = "读取方修改"
+	loaded.Data["k"] = "changed"
+	again, _ := store.Load(ctx, "t1")
+	if again.Card.MainTitle.Title != "原始" || again.Data["k"] != "v" {
+		t.Errorf("Load returned the stored session: %+v", again)
+	}
+}
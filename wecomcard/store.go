@@ -0,0 +1,114 @@
+package wecomcard
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-sphere/wecom-bot-api/wecomapi"
+)
+
+// ErrSessionNotFound 卡片会话不存在或已过期。
+var ErrSessionNotFound = errors.New("wecomcard: session not found")
+
+// Session 一张已发送的交互卡片及其上下文。
+type Session struct {
+	TaskID    string                 `json:"task_id"`             // 卡片任务ID
+	Handler   string                 `json:"handler"`             // 处理交互事件的回调名称
+	Card      *wecomapi.TemplateCard `json:"card"`                // 发送的原始卡片
+	ChatID    string                 `json:"chat_id,omitempty"`   // 会话ID（群聊）
+	UserIDs   []string               `json:"user_ids,omitempty"`  // 卡片接收者
+	Data      map[string]string      `json:"data,omitempty"`      // 应用自定义数据
+	CreatedAt time.Time              `json:"created_at"`          // 创建时间
+	ExpiresAt time.Time              `json:"expires_at,omitzero"` // 过期时间，零值表示不过期
+}
+
+// Expired 判断会话在指定时间是否已过期。
+func (s *Session) Expired(now time.Time) bool {
+	return !s.ExpiresAt.IsZero() && !now.Before(s.ExpiresAt)
+}
+
+// Clone 返回会话的深拷贝。
+func (s *Session) Clone() *Session {
+	if s == nil {
+		return nil
+	}
+	out := *s
+	out.Card = s.Card.Clone()
+	out.UserIDs = slices.Clone(s.UserIDs)
+	out.Data = maps.Clone(s.Data)
+	return &out
+}
+
+// Store 卡片会话存储。
+type Store interface {
+	// Save 保存会话，已存在时覆盖。
+	Save(ctx context.Context, s *Session) error
+	// Load 读取会话，不存在或已过期时返回 ErrSessionNotFound。
+	Load(ctx context.Context, taskID string) (*Session, error)
+	// Delete 删除会话，不存在时不报错。
+	Delete(ctx context.Context, taskID string) error
+}
+
+// sweepInterval 内存存储清理过期会话的最小间隔。
+const sweepInterval = time.Minute
+
+// MemoryStore 基于内存的会话存储，适用于单实例部署。
+// 保存和读取时都会复制会话，调用方修改返回值不影响已保存的内容。
+type MemoryStore struct {
+	mu        sync.Mutex
+	sessions  map[string]*Session
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryStore 创建内存会话存储。
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		sessions: make(map[string]*Session),
+		now:      time.Now,
+	}
+}
+
+// Save 保存会话，并定期清理过期会话。
+func (m *MemoryStore) Save(_ context.Context, s *Session) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	if now.Sub(m.lastSweep) >= sweepInterval {
+		for id, sess := range m.sessions {
+			if sess.Expired(now) {
+				delete(m.sessions, id)
+			}
+		}
+		m.lastSweep = now
+	}
+	m.sessions[s.TaskID] = s.Clone()
+	return nil
+}
+
+// Load 读取会话。
+func (m *MemoryStore) Load(_ context.Context, taskID string) (*Session, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s, ok := m.sessions[taskID]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	if s.Expired(m.now()) {
+		delete(m.sessions, taskID)
+		return nil, ErrSessionNotFound
+	}
+	return s.Clone(), nil
+}
+
+// Delete 删除会话。
+func (m *MemoryStore) Delete(_ context.Context, taskID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, taskID)
+	return nil
+}
@@ -0,0 +1,366 @@
+// Package wecomchart 以纯Go绘制简单的柱状图和折线图并编码为PNG，
+// 可作为流式消息结束时的图片附带。
+//
+// 内置点阵字体仅支持数字、英文字母和常用符号，包含其他字符（如中文）的
+// 标签以 "#序号" 代替，标题中不支持的字符绘制为 '?'，可在消息正文中说明对应关系。
+package wecomchart
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/go-sphere/wecom-bot-api/wecomapi"
+)
+
+// Kind 图表类型。
+type Kind int
+
+const (
+	KindBar Kind = iota
+	KindLine
+)
+
+// 默认尺寸。
+const (
+	DefaultWidth  = 800
+	DefaultHeight = 480
+)
+
+// fontScale 字体放大倍数。
+const fontScale = 2
+
+// Palette 默认的系列配色。
+var Palette = []color.RGBA{
+	{0x3B, 0x82, 0xF6, 0xFF},
+	{0xF5, 0x9E, 0x0B, 0xFF},
+	{0x10, 0xB9, 0x81, 0xFF},
+	{0xEF, 0x44, 0x44, 0xFF},
+	{0x8B, 0x5C, 0xF6, 0xFF},
+	{0x06, 0xB6, 0xD4, 0xFF},
+}
+
+var (
+	colorBackground = color.RGBA{0xFF, 0xFF, 0xFF, 0xFF}
+	colorAxis       = color.RGBA{0x37, 0x41, 0x51, 0xFF}
+	colorGrid       = color.RGBA{0xE5, 0xE7, 0xEB, 0xFF}
+	colorText       = color.RGBA{0x1F, 0x29, 0x37, 0xFF}
+)
+
+// Series 一组数据，Values 与 Chart.Labels 一一对应。
+type Series struct {
+	Name   string
+	Values []float64
+	Color  color.Color // 为nil时使用 Palette
+}
+
+// Chart 图表定义。
+type Chart struct {
+	Kind   Kind
+	Title  string
+	Labels []string
+	Series []Series
+	Width  int // 为0时使用 DefaultWidth
+	Height int // 为0时使用 DefaultHeight
+}
+
+// NewBarChart 创建柱状图。
+func NewBarChart(title string, labels []string, series ...Series) *Chart {
+	return &Chart{Kind: KindBar, Title: title, Labels: labels, Series: series}
+}
+
+// NewLineChart 创建折线图。
+func NewLineChart(title string, labels []string, series ...Series) *Chart {
+	return &Chart{Kind: KindLine, Title: title, Labels: labels, Series: series}
+}
+
+// Validate 检查图表数据。
+func (c *Chart) Validate() error {
+	if len(c.Labels) == 0 {
+		return errors.New("wecomchart: no labels")
+	}
+	if len(c.Series) == 0 {
+		return errors.New("wecomchart: no series")
+	}
+	for i, s := range c.Series {
+		if len(s.Values) != len(c.Labels) {
+			return fmt.Errorf("wecomchart: series %d has %d values, want %d", i, len(s.Values), len(c.Labels))
+		}
+		for _, v := range s.Values {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				return fmt.Errorf("wecomchart: series %d has non-finite value", i)
+			}
+		}
+	}
+	return nil
+}
+
+// Image 绘制图表。
+func (c *Chart) Image() (*image.RGBA, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	width, height := c.Width, c.Height
+	if width <= 0 {
+		width = DefaultWidth
+	}
+	if height <= 0 {
+		height = DefaultHeight
+	}
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(colorBackground), image.Point{}, draw.Src)
+
+	lo, hi, step := c.scale()
+	ticks := []string{}
+	for v := lo; v <= hi+step/2; v += step {
+		ticks = append(ticks, formatTick(v, step))
+	}
+	lineHeight := glyphHeight * fontScale
+	left := 16
+	for _, t := range ticks {
+		left = max(left, textWidth(t, fontScale)+16)
+	}
+	top := 16
+	if c.Title != "" {
+		drawText(img, (width-textWidth(c.Title, fontScale))/2, top, c.Title, fontScale, colorText)
+		top += lineHeight + 12
+	}
+	if len(c.Series) > 1 {
+		top = c.drawLegend(img, left, top, width)
+	}
+	// image.Rect 会交换颠倒的坐标，直接构造矩形以便识别尺寸过小的图片。
+	plot := image.Rectangle{Min: image.Pt(left, top+lineHeight/2), Max: image.Pt(width-20, height-lineHeight-24)}
+	if plot.Dx() <= 0 || plot.Dy() <= 0 {
+		return nil, errors.New("wecomchart: image too small")
+	}
+	y := func(v float64) int {
+		return plot.Max.Y - int(math.Round((v-lo)/(hi-lo)*float64(plot.Dy())))
+	}
+	for i, t := range ticks {
+		ty := y(lo + float64(i)*step)
+		fillRect(img, plot.Min.X, ty, plot.Dx(), 1, colorGrid)
+		drawText(img, plot.Min.X-8-textWidth(t, fontScale), ty-lineHeight/2, t, fontScale, colorText)
+	}
+
+	group := float64(plot.Dx()) / float64(len(c.Labels))
+	c.drawLabels(img, plot, group)
+	switch c.Kind {
+	case KindLine:
+		c.drawLines(img, plot, group, y)
+	default:
+		c.drawBars(img, plot, group, y(math.Max(lo, 0)), y)
+	}
+	fillRect(img, plot.Min.X, plot.Min.Y, 2, plot.Dy()+1, colorAxis)
+	fillRect(img, plot.Min.X, y(math.Max(lo, 0)), plot.Dx(), 2, colorAxis)
+	return img, nil
+}
+
+// Encode 将图表编码为PNG写入w。
+func (c *Chart) Encode(w io.Writer) error {
+	img, err := c.Image()
+	if err != nil {
+		return err
+	}
+	return png.Encode(w, img)
+}
+
+// PNG 返回图表的PNG编码。
+func (c *Chart) PNG() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := c.Encode(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// StreamImage 返回图表的Base64编码图片，可通过 wecomapi.WithStreamImages 附带在流式消息中。
+func (c *Chart) StreamImage() (*wecomapi.ImageBase64, error) {
+	data, err := c.PNG()
+	if err != nil {
+		return nil, err
+	}
+	return wecomapi.NewImageBase64(data), nil
+}
+
+func (c *Chart) seriesColor(i int) color.Color {
+	if c.Series[i].Color != nil {
+		return c.Series[i].Color
+	}
+	return Palette[i%len(Palette)]
+}
+
+// scale 计算纵轴范围和刻度间隔，范围总是包含0。
+func (c *Chart) scale() (lo, hi, step float64) {
+	for _, s := range c.Series {
+		for _, v := range s.Values {
+			lo, hi = math.Min(lo, v), math.Max(hi, v)
+		}
+	}
+	if hi == lo {
+		hi = lo + 1
+	}
+	step = niceStep((hi - lo) / 5)
+	return math.Floor(lo/step) * step, math.Ceil(hi/step) * step, step
+}
+
+// niceStep 将刻度间隔取整为1、2、5乘以10的幂。
+func niceStep(raw float64) float64 {
+	exp := math.Pow(10, math.Floor(math.Log10(raw)))
+	switch f := raw / exp; {
+	case f <= 1:
+		return exp
+	case f <= 2:
+		return 2 * exp
+	case f <= 5:
+		return 5 * exp
+	}
+	return 10 * exp
+}
+
+// formatTick 格式化刻度值，较大的值使用K、M后缀。
+func formatTick(v, step float64) string {
+	if math.Abs(v) < step/2 {
+		return "0"
+	}
+	suffix := ""
+	switch {
+	case math.Abs(step) >= 1e6:
+		v, step, suffix = v/1e6, step/1e6, "M"
+	case math.Abs(step) >= 1e3:
+		v, step, suffix = v/1e3, step/1e3, "K"
+	}
+	prec := max(0, -int(math.Floor(math.Log10(step))))
+	return strconv.FormatFloat(v, 'f', prec, 64) + suffix
+}
+
+func (c *Chart) drawLegend(img *image.RGBA, left, top, width int) int {
+	x, lineHeight := left, glyphHeight*fontScale
+	for i, s := range c.Series {
+		name := s.Name
+		if !supported(name) {
+			name = "#" + strconv.Itoa(i+1)
+		}
+		w := lineHeight + 6 + textWidth(name, fontScale) + 20
+		if x+w > width-20 && x > left {
+			x = left
+			top += lineHeight + 8
+		}
+		fillRect(img, x, top, lineHeight, lineHeight, c.seriesColor(i))
+		drawText(img, x+lineHeight+6, top, name, fontScale, colorText)
+		x += w
+	}
+	return top + lineHeight + 12
+}
+
+// drawLabels 在横轴下方绘制标签，标签过密时间隔绘制。
+func (c *Chart) drawLabels(img *image.RGBA, plot image.Rectangle, group float64) {
+	labels := make([]string, len(c.Labels))
+	widest := 0
+	for i, l := range c.Labels {
+		if !supported(l) {
+			l = "#" + strconv.Itoa(i+1)
+		}
+		labels[i] = l
+		widest = max(widest, textWidth(l, fontScale))
+	}
+	every := max(1, int(math.Ceil(float64(widest+8)/group)))
+	for i, l := range labels {
+		if i%every != 0 {
+			continue
+		}
+		cx := plot.Min.X + int(group*(float64(i)+0.5))
+		drawText(img, cx-textWidth(l, fontScale)/2, plot.Max.Y+10, l, fontScale, colorText)
+	}
+}
+
+func (c *Chart) drawBars(img *image.RGBA, plot image.Rectangle, group float64, base int, y func(float64) int) {
+	barWidth := group * 0.8 / float64(len(c.Series))
+	for i := range c.Labels {
+		x0 := float64(plot.Min.X) + group*float64(i) + group*0.1
+		for j, s := range c.Series {
+			x := int(x0 + barWidth*float64(j))
+			top, bottom := y(s.Values[i]), base
+			if top > bottom {
+				top, bottom = bottom, top
+			}
+			fillRect(img, x, top, max(int(barWidth)-1, 1), bottom-top, c.seriesColor(j))
+		}
+	}
+}
+
+func (c *Chart) drawLines(img *image.RGBA, plot image.Rectangle, group float64, y func(float64) int) {
+	for j, s := range c.Series {
+		col := c.seriesColor(j)
+		var px, py int
+		for i, v := range s.Values {
+			x, vy := plot.Min.X+int(group*(float64(i)+0.5)), y(v)
+			if i > 0 {
+				drawLine(img, px, py, x, vy, 3, col)
+			}
+			fillRect(img, x-4, vy-4, 9, 9, col)
+			px, py = x, vy
+		}
+	}
+}
+
+// supported 判断文本是否可由内置字体绘制。
+func supported(s string) bool {
+	for _, r := range strings.ToUpper(s) {
+		if _, ok := glyphs[r]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func fillRect(img *image.RGBA, x, y, w, h int, c color.Color) {
+	draw.Draw(img, image.Rect(x, y, x+w, y+h).Intersect(img.Bounds()), image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+// drawLine 以给定粗细绘制线段。
+func drawLine(img *image.RGBA, x0, y0, x1, y1, thickness int, c color.Color) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := sign(x1-x0), sign(y1-y0)
+	err := dx + dy
+	half := thickness / 2
+	for {
+		fillRect(img, x0-half, y0-half, thickness, thickness, c)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func sign(n int) int {
+	switch {
+	case n > 0:
+		return 1
+	case n < 0:
+		return -1
+	}
+	return 0
+}
@@ -0,0 +1,88 @@
+package wecomchart
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image/png"
+	"math"
+	"testing"
+)
+
+func TestChartPNG(t *testing.T) {
+	labels := []string{"Mon", "Tue", "周三"}
+	tests := []struct {
+		name  string
+		chart *Chart
+		w, h  int
+	}{
+		{"bar default size", NewBarChart("Sales", labels, Series{Name: "A", Values: []float64{1, 2.5, -3}}), DefaultWidth, DefaultHeight},
+		{"line custom size", &Chart{Kind: KindLine, Title: "趋势", Labels: labels, Width: 320, Height: 200,
+			Series: []Series{{Name: "A", Values: []float64{0, 0, 0}}, {Name: "B", Values: []float64{1e6, 2e6, 3e6}}}}, 320, 200},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.chart.PNG()
+			if err != nil {
+				t.Fatal(err)
+			}
+			img, err := png.Decode(bytes.NewReader(data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if b := img.Bounds(); b.Dx() != tt.w || b.Dy() != tt.h {
+				t.Errorf("image is %dx%d, want %dx%d", b.Dx(), b.Dy(), tt.w, tt.h)
+			}
+			if r, g, b, _ := img.At(0, 0).RGBA(); r != 0xFFFF || g != 0xFFFF || b != 0xFFFF {
+				t.Errorf("background = %v, want white", img.At(0, 0))
+			}
+
+			si, err := tt.chart.StreamImage()
+			if err != nil {
+				t.Fatal(err)
+			}
+			raw, err := base64.StdEncoding.DecodeString(si.Base64)
+			if err != nil || !bytes.Equal(raw, data) {
+				t.Errorf("StreamImage does not carry the PNG: %v", err)
+			}
+		})
+	}
+}
+
+func TestChartValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		chart *Chart
+	}{
+		{"no labels", NewBarChart("", nil, Series{Values: nil})},
+		{"no series", NewBarChart("", []string{"a"})},
+		{"value count", NewBarChart("", []string{"a", "b"}, Series{Values: []float64{1}})},
+		{"nan", NewLineChart("", []string{"a"}, Series{Values: []float64{math.NaN()}})},
+		{"too small", &Chart{Labels: []string{"a"}, Series: []Series{{Values: []float64{1}}}, Width: 10, Height: 10}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.chart.PNG(); err == nil {
+				t.Error("invalid chart rendered")
+			}
+		})
+	}
+}
+
+func TestFormatTick(t *testing.T) {
+	tests := []struct {
+		v, step float64
+		want    string
+	}{
+		{0.0000001, 1, "0"},
+		{5, 1, "5"},
+		{0.5, 0.5, "0.5"},
+		{2500, 500, "2500"},
+		{3000, 1000, "3K"},
+		{3e6, 1e6, "3M"},
+	}
+	for _, tt := range tests {
+		if got := formatTick(tt.v, tt.step); got != tt.want {
+			t.Errorf("formatTick(%v, %v) = %q, want %q", tt.v, tt.step, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+// Package wecomimage 将任意常见格式的图片规范为流式消息可用的图片：
+// JPG或PNG格式，且编码后不超过10M。仅使用标准库的 image/* 包。
+package wecomimage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif" // 注册GIF解码器
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	"github.com/go-sphere/wecom-bot-api/wecomapi"
+)
+
+// MaxBytes 流式消息图片（Base64编码前）的最大字节数。
+const MaxBytes = 10 << 20
+
+// minDimension 缩小图片时的最小边长。
+const minDimension = 64
+
+// 格式名称，与 image.DecodeConfig 返回的名称一致。
+const (
+	FormatPNG  = "png"
+	FormatJPEG = "jpeg"
+)
+
+// maxPixels 允许解码的最大像素数，防止超大尺寸图片耗尽内存。
+const maxPixels = 100 << 20
+
+// ErrTooLarge 缩小到最小尺寸后仍超出大小限制。
+var ErrTooLarge = errors.New("wecomimage: image cannot fit the size limit")
+
+// jpegQualities 依次尝试的JPEG质量。
+var jpegQualities = []int{90, 80, 70, 60, 50}
+
+// Result 规范后的图片。
+type Result struct {
+	Data      []byte // 编码后的图片内容
+	Format    string // FormatPNG 或 FormatJPEG
+	Width     int
+	Height    int
+	Converted bool // 是否经过重新编码
+}
+
+// Base64 返回图片的Base64编码和MD5值。
+func (r *Result) Base64() *wecomapi.ImageBase64 {
+	return wecomapi.NewImageBase64(r.Data)
+}
+
+type config struct {
+	maxBytes     int
+	maxDimension int
+}
+
+// Option 配置图片规范。
+type Option func(*config)
+
+// WithMaxBytes 设置编码后的最大字节数，默认且最大为 MaxBytes。
+func WithMaxBytes(n int) Option {
+	return func(c *config) {
+		if n > 0 && n <= MaxBytes {
+			c.maxBytes = n
+		}
+	}
+}
+
+// WithMaxDimension 限制图片的最大边长，超出时等比缩小。
+func WithMaxDimension(px int) Option {
+	return func(c *config) {
+		c.maxDimension = px
+	}
+}
+
+// Prepare 规范图片并返回流式消息图片的Base64编码和MD5值。
+func Prepare(data []byte, opts ...Option) (*wecomapi.ImageBase64, error) {
+	r, err := Normalize(data, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return r.Base64(), nil
+}
+
+// PrepareReader 读取并规范图片，返回流式消息图片的Base64编码和MD5值。
+func PrepareReader(r io.Reader, opts ...Option) (*wecomapi.ImageBase64, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return Prepare(data, opts...)
+}
+
+// Normalize 规范图片：符合要求的JPG、PNG原样返回；其他格式（如GIF，取第一帧）转换为PNG；
+// 超出大小限制时依次尝试降低JPEG质量和等比缩小，直到符合限制。
+// 含透明通道的图片转为JPEG时以白色为背景。
+func Normalize(data []byte, opts ...Option) (*Result, error) {
+	cfg := config{maxBytes: MaxBytes}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	imgCfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("wecomimage: decode config: %w", err)
+	}
+	if imgCfg.Width*imgCfg.Height > maxPixels {
+		return nil, fmt.Errorf("wecomimage: image dimensions %dx%d too large", imgCfg.Width, imgCfg.Height)
+	}
+	if (format == FormatPNG || format == FormatJPEG) && len(data) <= cfg.maxBytes && cfg.fits(imgCfg.Width, imgCfg.Height) {
+		return &Result{Data: data, Format: format, Width: imgCfg.Width, Height: imgCfg.Height}, nil
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("wecomimage: decode: %w", err)
+	}
+	if b := img.Bounds(); !cfg.fits(b.Dx(), b.Dy()) {
+		scale := float64(cfg.maxDimension) / float64(max(b.Dx(), b.Dy()))
+		img = resize(img, int(float64(b.Dx())*scale), int(float64(b.Dy())*scale))
+	}
+	for {
+		if r, err := cfg.encode(img, format); err != nil || r != nil {
+			return r, err
+		}
+		b := img.Bounds()
+		w, h := b.Dx()*3/4, b.Dy()*3/4
+		if min(w, h) < minDimension {
+			return nil, ErrTooLarge
+		}
+		img = resize(img, w, h)
+	}
+}
+
+func (c *config) fits(w, h int) bool {
+	return c.maxDimension <= 0 || max(w, h) <= c.maxDimension
+}
+
+// encode 以当前尺寸编码图片，均超出限制时返回nil。
+// 源格式为JPEG时只尝试JPEG，否则先尝试PNG再尝试JPEG。
+func (c *config) encode(img image.Image, format string) (*Result, error) {
+	b := img.Bounds()
+	var buf bytes.Buffer
+	if format != FormatJPEG {
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, err
+		}
+		if buf.Len() <= c.maxBytes {
+			return &Result{Data: buf.Bytes(), Format: FormatPNG, Width: b.Dx(), Height: b.Dy(), Converted: true}, nil
+		}
+	}
+	opaque := flatten(img)
+	for _, q := range jpegQualities {
+		buf.Reset()
+		if err := jpeg.Encode(&buf, opaque, &jpeg.Options{Quality: q}); err != nil {
+			return nil, err
+		}
+		if buf.Len() <= c.maxBytes {
+			return &Result{Data: buf.Bytes(), Format: FormatJPEG, Width: b.Dx(), Height: b.Dy(), Converted: true}, nil
+		}
+	}
+	return nil, nil
+}
+
+// flatten 将图片绘制到白色背景上，去除透明通道。
+func flatten(img image.Image) image.Image {
+	if o, ok := img.(interface{ Opaque() bool }); ok && o.Opaque() {
+		return img
+	}
+	b := img.Bounds()
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Over)
+	return dst
+}
+
+// resize 以区域平均将图片缩小到w×h。
+func resize(img image.Image, w, h int) *image.RGBA {
+	src := image.NewRGBA(image.Rect(0, 0, img.Bounds().Dx(), img.Bounds().Dy()))
+	draw.Draw(src, src.Bounds(), img, img.Bounds().Min, draw.Src)
+	sw, sh := src.Bounds().Dx(), src.Bounds().Dy()
+	w, h = max(w, 1), max(h, 1)
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		y0, y1 := y*sh/h, max((y+1)*sh/h, y*sh/h+1)
+		for x := range w {
+			x0, x1 := x*sw/w, max((x+1)*sw/w, x*sw/w+1)
+			var r, g, b, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				i := src.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					r += uint32(src.Pix[i])
+					g += uint32(src.Pix[i+1])
+					b += uint32(src.Pix[i+2])
+					a += uint32(src.Pix[i+3])
+					i += 4
+					n++
+				}
+			}
+			i := dst.PixOffset(x, y)
+			dst.Pix[i] = uint8(r / n)
+			dst.Pix[i+1] = uint8(g / n)
+			dst.Pix[i+2] = uint8(b / n)
+			dst.Pix[i+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,161 @@
+package wecomimage
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"math/rand/v2"
+	"testing"
+)
+
+// noise 生成随机像素的图片，便于构造难以压缩的数据。
+func noise(w, h int) *image.RGBA {
+	rng := rand.New(rand.NewPCG(1, 2))
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(rng.UintN(256))
+		if i%4 == 3 {
+			img.Pix[i] = 0xFF
+		}
+	}
+	return img
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func encodeJPEG(t *testing.T, img image.Image, quality int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// decoded 检查结果可按声明的格式解码且尺寸一致。
+func decoded(t *testing.T, r *Result) image.Image {
+	t.Helper()
+	img, format, err := image.Decode(bytes.NewReader(r.Data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format != r.Format {
+		t.Errorf("data is %s, Result.Format = %s", format, r.Format)
+	}
+	if b := img.Bounds(); b.Dx() != r.Width || b.Dy() != r.Height {
+		t.Errorf("data is %dx%d, Result is %dx%d", b.Dx(), b.Dy(), r.Width, r.Height)
+	}
+	return img
+}
+
+func TestNormalizePassThrough(t *testing.T) {
+	for name, data := range map[string][]byte{
+		FormatPNG:  encodePNG(t, noise(20, 10)),
+		FormatJPEG: encodeJPEG(t, noise(20, 10), 90),
+	} {
+		r, err := Normalize(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Converted || r.Format != name || !bytes.Equal(r.Data, data) || r.Width != 20 || r.Height != 10 {
+			t.Errorf("%s: Result = %+v", name, r)
+		}
+	}
+}
+
+func TestNormalizeGIF(t *testing.T) {
+	src := image.NewPaletted(image.Rect(0, 0, 30, 20), palette.Plan9)
+	src.Set(3, 4, color.RGBA{0xFF, 0, 0, 0xFF})
+	var buf bytes.Buffer
+	if err := gif.Encode(&buf, src, nil); err != nil {
+		t.Fatal(err)
+	}
+	r, err := Normalize(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.Converted || r.Format != FormatPNG || r.Width != 30 || r.Height != 20 {
+		t.Fatalf("Result = %+v", r)
+	}
+	img := decoded(t, r)
+	if cr, _, _, _ := img.At(3, 4).RGBA(); cr != 0xFFFF {
+		t.Errorf("pixel (3,4) = %v, want red", img.At(3, 4))
+	}
+}
+
+func TestNormalizeJPEGQuality(t *testing.T) {
+	src := noise(128, 128)
+	data := encodeJPEG(t, src, 100)
+	q90, q70 := encodeJPEG(t, src, 90), encodeJPEG(t, src, 70)
+	limit := (len(q90) + len(q70)) / 2
+
+	r, err := Normalize(data, WithMaxBytes(limit))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.Converted || r.Format != FormatJPEG || r.Width != 128 || r.Height != 128 {
+		t.Fatalf("Result = %+v", r)
+	}
+	if len(r.Data) > limit || len(r.Data) < len(q70) {
+		t.Errorf("encoded %d bytes, want between %d (q70) and the limit %d", len(r.Data), len(q70), limit)
+	}
+	decoded(t, r)
+}
+
+func TestNormalizeDownscale(t *testing.T) {
+	t.Run("max dimension", func(t *testing.T) {
+		r, err := Normalize(encodePNG(t, noise(400, 200)), WithMaxDimension(100))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !r.Converted || r.Width != 100 || r.Height != 50 {
+			t.Errorf("Result = %dx%d converted=%v, want 100x50", r.Width, r.Height, r.Converted)
+		}
+		decoded(t, r)
+	})
+	t.Run("max bytes", func(t *testing.T) {
+		limit := len(encodeJPEG(t, noise(256, 128), 50))
+		r, err := Normalize(encodePNG(t, noise(512, 256)), WithMaxBytes(limit))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(r.Data) > limit || r.Format != FormatJPEG || r.Width >= 512 || r.Width != r.Height*2 {
+			t.Errorf("Result = %s %dx%d, %d bytes, limit %d", r.Format, r.Width, r.Height, len(r.Data), limit)
+		}
+		decoded(t, r)
+	})
+}
+
+func TestNormalizeTooLarge(t *testing.T) {
+	_, err := Normalize(encodePNG(t, noise(256, 256)), WithMaxBytes(100))
+	if !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Normalize = %v, want ErrTooLarge", err)
+	}
+	if _, err := Normalize([]byte("not an image")); err == nil {
+		t.Error("invalid data accepted")
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	src.Set(1, 0, color.NRGBA{0, 0, 0xFF, 0xFF})
+	img := flatten(src)
+	if r, g, b, a := img.At(0, 0).RGBA(); r != 0xFFFF || g != 0xFFFF || b != 0xFFFF || a != 0xFFFF {
+		t.Errorf("transparent pixel = %v, want white", img.At(0, 0))
+	}
+	if r, _, b, _ := img.At(1, 0).RGBA(); r != 0 || b != 0xFFFF {
+		t.Errorf("opaque pixel = %v, want blue", img.At(1, 0))
+	}
+}
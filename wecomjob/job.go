@@ -0,0 +1,125 @@
+package wecomjob
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-sphere/wecom-bot-api/wecomapi"
+)
+
+// 进度卡片的按钮Key。
+const (
+	KeyRefresh = "job_refresh"
+	KeyCancel  = "job_cancel"
+	KeyDone    = "job_done"
+)
+
+// Stage 任务阶段。
+type Stage string
+
+const (
+	StageQueued   Stage = "queued"
+	StageRunning  Stage = "running"
+	StageDone     Stage = "done"
+	StageFailed   Stage = "failed"
+	StageCanceled Stage = "canceled"
+)
+
+// Finished 判断任务是否已结束（完成、失败或取消）。
+func (s Stage) Finished() bool {
+	return s == StageDone || s == StageFailed || s == StageCanceled
+}
+
+// String 返回阶段的中文名称。
+func (s Stage) String() string {
+	switch s {
+	case StageQueued:
+		return "排队中"
+	case StageRunning:
+		return "运行中"
+	case StageDone:
+		return "已完成"
+	case StageFailed:
+		return "失败"
+	case StageCanceled:
+		return "已取消"
+	}
+	return string(s)
+}
+
+// Job 任务的当前状态快照。
+type Job struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Stage     Stage     `json:"stage"`
+	Percent   int       `json:"percent"`
+	Message   string    `json:"message,omitempty"`
+	ResultURL string    `json:"result_url,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	UserID    string    `json:"userid,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitzero"`
+	UpdatedAt time.Time `json:"updated_at,omitzero"`
+}
+
+// progressBarWidth 进度条的格数。
+const progressBarWidth = 10
+
+// ProgressBar 将百分比渲染为文本进度条，如 "▓▓▓▓░░░░░░ 40%"。
+func ProgressBar(percent int) string {
+	percent = min(max(percent, 0), 100)
+	n := percent * progressBarWidth / 100
+	return strings.Repeat("▓", n) + strings.Repeat("░", progressBarWidth-n) + fmt.Sprintf(" %d%%", percent)
+}
+
+// Card 根据任务当前状态生成按钮交互卡片：进行中显示刷新和取消按钮，
+// 完成后附带结果下载链接。
+func (j *Job) Card() (*wecomapi.TemplateCard, error) {
+	b := wecomapi.NewButtonInteractionCard().
+		MainTitle(j.Title, j.Stage.String()).
+		TaskID(j.ID)
+	if text := j.detail(); text != "" {
+		b.SubTitle(text)
+	}
+	items := []wecomapi.HorizontalContent{
+		wecomapi.HorizontalText("状态", j.Stage.String()),
+	}
+	if j.Stage == StageRunning || j.Stage == StageDone {
+		items = append(items, wecomapi.HorizontalText("进度", ProgressBar(j.Percent)))
+	}
+	if j.Stage == StageDone && j.ResultURL != "" {
+		items = append(items, wecomapi.HorizontalURL("结果", "点击下载", j.ResultURL))
+	}
+	b.Horizontal(items...)
+	switch j.Stage {
+	case StageQueued, StageRunning:
+		b.Button("刷新进度", KeyRefresh, wecomapi.ButtonStyleBlue)
+		b.Button("取消", KeyCancel, wecomapi.ButtonStyleRed)
+	default:
+		b.Button(j.Stage.String(), KeyDone, wecomapi.ButtonStyleGray)
+	}
+	return b.Build()
+}
+
+// Markdown 将任务状态渲染为Markdown文本，用于群聊等不支持模板卡片主动回复的场景。
+func (j *Job) Markdown() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "**%s**\n> 状态：%s\n", j.Title, j.Stage.String())
+	if j.Stage == StageRunning || j.Stage == StageDone {
+		fmt.Fprintf(&sb, "> 进度：%s\n", ProgressBar(j.Percent))
+	}
+	if text := j.detail(); text != "" {
+		fmt.Fprintf(&sb, "> %s\n", text)
+	}
+	if j.Stage == StageDone && j.ResultURL != "" {
+		fmt.Fprintf(&sb, "[点击下载](%s)\n", j.ResultURL)
+	}
+	return sb.String()
+}
+
+func (j *Job) detail() string {
+	if j.Stage == StageFailed && j.Error != "" {
+		return "错误：" + j.Error
+	}
+	return j.Message
+}
@@ -0,0 +1,343 @@
+package wecomjob
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-sphere/wecom-bot-api/wecomapi"
+	"github.com/go-sphere/wecom-bot-api/wecomcard"
+)
+
+// ErrJobNotFound 任务不存在或已被清理。
+var ErrJobNotFound = errors.New("wecomjob: job not found")
+
+// DefaultRetention 已结束任务的默认保留时长。
+const DefaultRetention = 24 * time.Hour
+
+// responseURLTTL response_url 的有效期。
+const responseURLTTL = time.Hour
+
+// RunFunc 后台任务的执行函数，返回结果下载链接。
+// ctx 在任务被取消时结束，执行过程中通过 p 上报进度。
+type RunFunc func(ctx context.Context, p *Progress) (resultURL string, err error)
+
+// Progress 供任务上报进度。
+type Progress struct {
+	m  *Manager
+	id string
+}
+
+// Update 更新任务进度，percent取值0-100，message显示在卡片副标题中。
+func (p *Progress) Update(percent int, message string) {
+	p.m.update(p.id, func(j *Job) {
+		if j.Stage.Finished() {
+			return
+		}
+		j.Stage = StageRunning
+		j.Percent = min(max(percent, 0), 100)
+		j.Message = message
+	})
+}
+
+type entry struct {
+	job         Job
+	cancel      context.CancelFunc
+	cb          *wecomapi.Callback // 启动任务的回调，用于主动推送结束状态
+	responseURL string             // 尚未使用的 response_url
+	delivered   bool               // 结束状态已通过卡片更新送达
+	alias       string             // 主动推送的卡片使用的task_id
+}
+
+// Manager 管理后台任务及其进度卡片。
+type Manager struct {
+	mu        sync.Mutex
+	jobs      map[string]*entry
+	aliases   map[string]string // 推送卡片的task_id -> 任务ID
+	sem       chan struct{}
+	client    *wecomapi.ActiveReplyClient
+	retention time.Duration
+	onError   func(job *Job, err error)
+	now       func() time.Time
+}
+
+// Option 配置 Manager。
+type Option func(*Manager)
+
+// WithConcurrency 限制同时运行的任务数，超出的任务处于排队状态。
+func WithConcurrency(n int) Option {
+	return func(m *Manager) {
+		if n > 0 {
+			m.sem = make(chan struct{}, n)
+		}
+	}
+}
+
+// WithActiveReplyClient 设置推送结束状态所用的主动回复客户端，默认使用 http.DefaultClient。
+// 调试时可传入启用 wecomapi.WithActiveReplyCheck 的客户端检查推送的回复。
+func WithActiveReplyClient(c *wecomapi.ActiveReplyClient) Option {
+	return func(m *Manager) {
+		m.client = c
+	}
+}
+
+// WithRetention 设置已结束任务的保留时长，默认为 DefaultRetention。
+func WithRetention(d time.Duration) Option {
+	return func(m *Manager) {
+		m.retention = d
+	}
+}
+
+// WithErrorHandler 设置主动回复推送失败时的回调。
+func WithErrorHandler(fn func(job *Job, err error)) Option {
+	return func(m *Manager) {
+		m.onError = fn
+	}
+}
+
+// NewManager 创建任务管理器。
+func NewManager(opts ...Option) *Manager {
+	m := &Manager{
+		jobs:      make(map[string]*entry),
+		aliases:   make(map[string]string),
+		client:    wecomapi.NewActiveReplyClient(nil),
+		retention: DefaultRetention,
+		now:       time.Now,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// Start 在后台启动任务，并返回排队状态的进度卡片回复。
+// 任务结束时，若用户尚未通过刷新按钮看到结束状态，则通过回调的 response_url 推送：
+// 单聊推送使用新task_id的模板卡片，群聊推送Markdown。
+func (m *Manager) Start(ctx context.Context, cb *wecomapi.Callback, title string, run RunFunc) (*wecomapi.PassiveReply, error) {
+	id, err := wecomcard.NewTaskID()
+	if err != nil {
+		return nil, err
+	}
+	now := m.now()
+	e := &entry{
+		job: Job{
+			ID:        id,
+			Title:     title,
+			Stage:     StageQueued,
+			UserID:    cb.From.UserID,
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+		cb:          cb,
+		responseURL: cb.ResponseURL,
+	}
+	card, err := e.job.Card()
+	if err != nil {
+		return nil, err
+	}
+	runCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	e.cancel = cancel
+
+	m.mu.Lock()
+	m.sweep(now)
+	m.jobs[id] = e
+	m.mu.Unlock()
+
+	go m.run(runCtx, id, run)
+	return wecomapi.NewTemplateCardReply(card), nil
+}
+
+func (m *Manager) run(ctx context.Context, id string, run RunFunc) {
+	if m.sem != nil {
+		select {
+		case m.sem <- struct{}{}:
+			defer func() { <-m.sem }()
+		case <-ctx.Done():
+			return
+		}
+	}
+	m.update(id, func(j *Job) {
+		if j.Stage == StageQueued {
+			j.Stage = StageRunning
+		}
+	})
+	resultURL, err := safeRun(ctx, &Progress{m: m, id: id}, run)
+	m.finish(ctx, id, false, func(j *Job) {
+		switch {
+		case ctx.Err() != nil:
+			j.Stage = StageCanceled
+		case err != nil:
+			j.Stage = StageFailed
+			j.Error = err.Error()
+		default:
+			j.Stage = StageDone
+			j.Percent = 100
+			j.Message = ""
+			j.ResultURL = resultURL
+		}
+	})
+}
+
+func safeRun(ctx context.Context, p *Progress, run RunFunc) (resultURL string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("wecomjob: job panicked: %v", r)
+		}
+	}()
+	return run(ctx, p)
+}
+
+// Get 返回任务的当前状态。
+func (m *Manager) Get(id string) (Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.jobs[id]
+	if !ok {
+		return Job{}, ErrJobNotFound
+	}
+	return e.job, nil
+}
+
+// Cancel 取消未结束的任务，并通过 response_url 推送取消状态。
+func (m *Manager) Cancel(ctx context.Context, id string) error {
+	_, err := m.cancel(ctx, id, false)
+	return err
+}
+
+func (m *Manager) cancel(ctx context.Context, id string, delivered bool) (Job, error) {
+	return m.finish(ctx, id, delivered, func(j *Job) {
+		j.Stage = StageCanceled
+	})
+}
+
+// HandleEvent 处理进度卡片的刷新和取消按钮，返回最新状态的卡片更新，可直接注册到 wecomapi.Router。
+func (m *Manager) HandleEvent(ctx context.Context, cb *wecomapi.Callback) (*wecomapi.PassiveReply, error) {
+	if cb.Event == nil || cb.Event.TemplateCardEvent == nil {
+		return nil, errors.New("wecomjob: callback is not a template card event")
+	}
+	ev := cb.Event.TemplateCardEvent
+	id := m.resolve(ev.TaskID)
+	var job Job
+	var err error
+	if ev.EventKey == KeyCancel {
+		job, err = m.cancel(ctx, id, true)
+	} else {
+		job, err = m.snapshot(id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	card, err := job.Card()
+	if err != nil {
+		return nil, err
+	}
+	// 更新的是被点击的那张卡片，推送的卡片使用别名task_id。
+	card.TaskID = ev.TaskID
+	return wecomapi.NewUpdateTemplateCardReply([]string{cb.From.UserID}, card), nil
+}
+
+// resolve 将推送卡片的task_id映射回任务ID。
+func (m *Manager) resolve(taskID string) string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if id, ok := m.aliases[taskID]; ok {
+		return id
+	}
+	return taskID
+}
+
+// snapshot 返回任务状态，若任务已结束则标记结束状态已送达。
+func (m *Manager) snapshot(id string) (Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.jobs[id]
+	if !ok {
+		return Job{}, ErrJobNotFound
+	}
+	if e.job.Stage.Finished() {
+		e.delivered = true
+	}
+	return e.job, nil
+}
+
+func (m *Manager) update(id string, fn func(j *Job)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if e, ok := m.jobs[id]; ok {
+		fn(&e.job)
+		e.job.UpdatedAt = m.now()
+	}
+}
+
+// finish 将任务置为结束状态，已结束的任务保持不变。delivered 表示结束状态将由本次卡片更新送达，
+// 否则，则通过 response_url 推送。
+func (m *Manager) finish(ctx context.Context, id string, delivered bool, fn func(j *Job)) (Job, error) {
+	m.mu.Lock()
+	e, ok := m.jobs[id]
+	if !ok {
+		m.mu.Unlock()
+		return Job{}, ErrJobNotFound
+	}
+	if e.job.Stage.Finished() {
+		job := e.job
+		m.mu.Unlock()
+		return job, nil
+	}
+	fn(&e.job)
+	e.job.UpdatedAt = m.now()
+	e.cancel()
+	if delivered {
+		e.delivered = true
+	}
+	job, push := e.job, !e.delivered && e.responseURL != "" && m.now().Sub(e.job.CreatedAt) < responseURLTTL
+	cb := e.cb
+	if push {
+		// response_url 只能使用一次。
+		e.responseURL = ""
+	}
+	m.mu.Unlock()
+
+	if push {
+		if err := m.push(context.WithoutCancel(ctx), id, cb, &job); err != nil && m.onError != nil {
+			m.onError(&job, err)
+		}
+	}
+	return job, nil
+}
+
+// push 通过 response_url 推送结束状态。同一机器人的task_id不能重复，
+// 推送的卡片使用新的task_id，并记录为任务的别名。
+func (m *Manager) push(ctx context.Context, id string, cb *wecomapi.Callback, job *Job) error {
+	reply := wecomapi.NewMarkdownReply(job.Markdown())
+	if cb.ChatType == wecomapi.ChatTypeSingle {
+		card, err := job.Card()
+		if err != nil {
+			return err
+		}
+		alias, err := wecomcard.NewTaskID()
+		if err != nil {
+			return err
+		}
+		card.TaskID = alias
+		m.mu.Lock()
+		if e, ok := m.jobs[id]; ok {
+			e.alias = alias
+			m.aliases[alias] = id
+		}
+		m.mu.Unlock()
+		reply = wecomapi.NewTemplateCardReply(card)
+	}
+	return m.client.SendFor(ctx, cb, reply)
+}
+
+// sweep 清理超过保留时长的已结束任务，调用方需持有锁。
+func (m *Manager) sweep(now time.Time) {
+	for id, e := range m.jobs {
+		if e.job.Stage.Finished() && now.Sub(e.job.UpdatedAt) > m.retention {
+			delete(m.jobs, id)
+			delete(m.aliases, e.alias)
+		}
+	}
+}
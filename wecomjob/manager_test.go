@@ -0,0 +1,103 @@
+package wecomjob
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-sphere/wecom-bot-api/wecomapi"
+)
+
+// pushServer 记录通过 response_url 推送的回复。
+func pushServer(t *testing.T) (*httptest.Server, <-chan *wecomapi.PassiveReply) {
+	t.Helper()
+	pushed := make(chan *wecomapi.PassiveReply, 4)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var reply wecomapi.PassiveReply
+		if err := json.NewDecoder(r.Body).Decode(&reply); err != nil {
+			t.Errorf("decode pushed reply: %v", err)
+		}
+		pushed <- &reply
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, pushed
+}
+
+func cardEvent(taskID, key string) *wecomapi.Callback {
+	return &wecomapi.Callback{
+		MsgType:  wecomapi.CallbackMsgTypeEvent,
+		ChatType: wecomapi.ChatTypeSingle,
+		From:     wecomapi.From{UserID: "u1"},
+		Event: &wecomapi.Event{
+			EventType: wecomapi.EventTypeTemplateCard,
+			TemplateCardEvent: &wecomapi.TemplateCardEvent{
+				CardType: wecomapi.TemplateCardTypeButtonInteraction,
+				TaskID:   taskID,
+				EventKey: key,
+			},
+		},
+	}
+}
+
+func TestPushUsesFreshTaskID(t *testing.T) {
+	srv, pushed := pushServer(t)
+	m := NewManager()
+	cb := &wecomapi.Callback{
+		MsgType:     wecomapi.CallbackMsgTypeText,
+		ChatType:    wecomapi.ChatTypeSingle,
+		From:        wecomapi.From{UserID: "u1"},
+		ResponseURL: srv.URL,
+	}
+	reply, err := m.Start(context.Background(), cb, "导出", func(ctx context.Context, p *Progress) (string, error) {
+		return "https://example.com/result", nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	jobID := reply.TemplateCard.TaskID
+
+	var push *wecomapi.PassiveReply
+	select {
+	case push = <-pushed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("finished job was not pushed")
+	}
+	if push.TemplateCard == nil {
+		t.Fatalf("pushed %+v, want template card", push)
+	}
+	alias := push.TemplateCard.TaskID
+	if alias == "" || alias == jobID {
+		t.Fatalf("pushed card task_id %q reuses job task_id %q", alias, jobID)
+	}
+
+	update, err := m.HandleEvent(context.Background(), cardEvent(alias, KeyRefresh))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if update.ResponseType != wecomapi.ReplyResponseTypeUpdateTemplateCard {
+		t.Errorf("response_type = %q", update.ResponseType)
+	}
+	if update.TemplateCard.TaskID != alias {
+		t.Errorf("update task_id = %q, want %q", update.TemplateCard.TaskID, alias)
+	}
+	job, err := m.Get(jobID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Stage != StageDone {
+		t.Errorf("stage = %v, want done", job.Stage)
+	}
+
+	// 原卡片仍按任务ID更新。
+	update, err = m.HandleEvent(context.Background(), cardEvent(jobID, KeyRefresh))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if update.TemplateCard.TaskID != jobID {
+		t.Errorf("update task_id = %q, want %q", update.TemplateCard.TaskID, jobID)
+	}
+}
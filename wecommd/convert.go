@@ -0,0 +1,365 @@
+// Package wecommd 将通用Markdown（CommonMark/GFM）转换为企业微信 markdown-v2 支持的子集。
+//
+// 企业微信支持一至三级标题、加粗、斜体、有序和无序列表、三级以内的引用、链接、图片、
+// 分割线、行内代码、代码块和表格。其余语法在转换时改写或去除：
+//   - 四级及以下标题改为加粗文本；
+//   - 常见的HTML标签（见 htmlTags）和注释被去除，保留其中的文本，<br> 改为换行，
+//     其余尖括号内容（如 Vec<String>）原样保留；
+//   - 脚注引用改为 [n]，脚注定义汇总到末尾；
+//   - 任务列表改为 ☐/☑ 符号；
+//   - 删除线去除标记，引用式链接改为行内链接；
+//   - 表格补齐首尾竖线、统一列数并重建分隔行，超出表头列数的单元格并入最后一列；
+//   - ~~~ 和多于三个反引号的代码块统一为 ```，保留其缩进（如列表中的代码块）。
+package wecommd
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MaxQuoteDepth 企业微信支持的最大引用层级。
+const MaxQuoteDepth = 3
+
+var (
+	reHeading     = regexp.MustCompile(`^ {0,3}(#{1,6})\s+(.*?)(?:\s+#+)?\s*$`)
+	reSetext      = regexp.MustCompile(`^ {0,3}=+\s*$`)
+	reFence       = regexp.MustCompile("^([ \t]*)(`{3,}|~{3,})\\s*([^`]*)$")
+	reTaskItem    = regexp.MustCompile(`^(\s*(?:[-*+]|\d+[.)])\s+)\[([ xX])\]\s+`)
+	reFootnoteDef = regexp.MustCompile(`^ {0,3}\[\^([^\]]+)\]:\s*(.*)$`)
+	reRefDef      = regexp.MustCompile(`^ {0,3}\[([^\]^][^\]]*)\]:\s*<?(\S+?)>?(?:\s+(?:"[^"]*"|'[^']*'|\([^)]*\)))?\s*$`)
+	reFootnoteRef = regexp.MustCompile(`\[\^([^\]]+)\]`)
+	reRefLink     = regexp.MustCompile(`(!?)\[([^\]]+)\]\[([^\]]*)\]`)
+	reAutolink    = regexp.MustCompile(`<((?:https?|mailto):[^>\s]+)>`)
+	reImage       = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]*)(?:\s+"[^"]*")?\)`)
+	reStrike      = regexp.MustCompile(`~~([^~]+)~~`)
+	reBreak       = regexp.MustCompile(`(?i)<br\s*/?>`)
+	reTag         = regexp.MustCompile(`</?([a-zA-Z][a-zA-Z0-9-]*)(?:\s[^<>]*)?/?>`)
+	reComment     = regexp.MustCompile(`<!--.*?-->`)
+	reQuote       = regexp.MustCompile(`^ {0,3}((?:>\s?)+)`)
+	reTableSep    = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(?:\|\s*:?-+:?\s*)*\|?\s*$`)
+)
+
+// htmlTags 转换时去除的HTML标签，不在其中的尖括号内容（如泛型参数）原样保留。
+var htmlTags = map[string]bool{
+	"a": true, "abbr": true, "article": true, "aside": true, "b": true, "big": true,
+	"blockquote": true, "caption": true, "center": true, "cite": true, "code": true,
+	"dd": true, "del": true, "details": true, "div": true, "dl": true, "dt": true,
+	"em": true, "figcaption": true, "figure": true, "font": true, "footer": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"header": true, "hr": true, "i": true, "img": true, "ins": true, "kbd": true,
+	"li": true, "mark": true, "nav": true, "ol": true, "p": true, "pre": true,
+	"q": true, "s": true, "samp": true, "section": true, "small": true, "span": true,
+	"strike": true, "strong": true, "sub": true, "summary": true, "sup": true,
+	"table": true, "tbody": true, "td": true, "tfoot": true, "th": true, "thead": true,
+	"tr": true, "tt": true, "u": true, "ul": true, "var": true,
+}
+
+// Convert 将通用Markdown转换为企业微信 markdown-v2 支持的子集。
+func Convert(md string) string {
+	c := newConverter()
+	// 预先收集引用式链接的定义，以便解析定义之前出现的引用。
+	for line := range strings.Lines(md) {
+		c.collectRef(strings.TrimRight(line, "\r\n"))
+	}
+	var sb strings.Builder
+	c.out = &sb
+	for line := range strings.Lines(md) {
+		c.feed(strings.TrimRight(line, "\r\n"))
+	}
+	c.flush()
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+type converter struct {
+	out       *strings.Builder
+	fence     string // 未闭合代码块的原始标记
+	indent    string // 未闭合代码块起始行的缩进
+	comment   bool   // 处于多行HTML注释中
+	held      string // 可能是表头的行
+	hasHeld   bool
+	tableCols int // 当前表格的列数，0表示不在表格中
+	refs      map[string]string
+	notes     []string
+	noteIDs   map[string]int
+}
+
+func newConverter() *converter {
+	return &converter{refs: make(map[string]string), noteIDs: make(map[string]int)}
+}
+
+func (c *converter) emit(line string) {
+	c.out.WriteString(line)
+	c.out.WriteByte('\n')
+}
+
+func (c *converter) collectRef(line string) {
+	if m := reRefDef.FindStringSubmatch(line); m != nil {
+		c.refs[strings.ToLower(m[1])] = m[2]
+	}
+}
+
+// feed 转换一行，可能因等待表格分隔行而暂存。
+func (c *converter) feed(line string) {
+	if c.fence != "" {
+		c.fenceLine(line)
+		return
+	}
+	if c.hasHeld {
+		held := c.held
+		c.held, c.hasHeld = "", false
+		if reTableSep.MatchString(line) && strings.Contains(line, "-") {
+			cells := splitRow(held)
+			c.tableCols = len(cells)
+			c.emit(c.row(cells))
+			c.emit(separator(splitRow(line), c.tableCols))
+			return
+		}
+		c.block(held)
+	}
+	if c.tableCols > 0 {
+		if isTableRow(line) {
+			c.emit(c.row(splitRow(line)))
+			return
+		}
+		c.tableCols = 0
+	}
+	if isTableRow(line) && !reFence.MatchString(line) {
+		c.held, c.hasHeld = line, true
+		return
+	}
+	c.block(line)
+}
+
+// flush 输出暂存的行、闭合未结束的代码块，并追加脚注。
+func (c *converter) flush() {
+	if c.hasHeld {
+		c.block(c.held)
+		c.held, c.hasHeld = "", false
+	}
+	if c.fence != "" {
+		c.emit(c.indent + "```")
+		c.fence, c.indent = "", ""
+	}
+	if len(c.notes) > 0 {
+		c.emit("")
+		c.emit("---")
+		for i, note := range c.notes {
+			c.emit(strconv.Itoa(i+1) + ". " + c.inline(note))
+		}
+		c.notes = nil
+	}
+}
+
+func (c *converter) fenceLine(line string) {
+	trimmed := strings.TrimSpace(line)
+	if strings.HasPrefix(trimmed, c.fence) && strings.Trim(trimmed, c.fence[:1]) == "" {
+		c.emit(c.indent + "```")
+		c.fence, c.indent = "", ""
+		return
+	}
+	if len(c.fence) > 3 && strings.HasPrefix(trimmed, "```") {
+		// 外层代码块统一为 ``` 后，内部的 ``` 需缩进以免提前闭合。
+		line = "    " + line
+	}
+	c.emit(line)
+}
+
+func (c *converter) block(line string) {
+	if m := reFence.FindStringSubmatch(line); m != nil {
+		c.indent, c.fence = m[1], m[2]
+		c.emit(c.indent + "```" + strings.TrimSpace(m[3]))
+		return
+	}
+	line = c.stripComments(line)
+	if strings.TrimSpace(line) == "" {
+		c.emit("")
+		return
+	}
+	if m := reFootnoteDef.FindStringSubmatch(line); m != nil {
+		c.noteID(m[1])
+		c.notes[c.noteIDs[m[1]]-1] = m[2]
+		return
+	}
+	if reRefDef.MatchString(line) {
+		c.collectRef(line)
+		return
+	}
+	if reSetext.MatchString(line) {
+		return
+	}
+	if m := reHeading.FindStringSubmatch(line); m != nil {
+		text := c.inline(m[2])
+		if len(m[1]) > 3 {
+			c.emit("**" + text + "**")
+		} else {
+			c.emit(m[1] + " " + text)
+		}
+		return
+	}
+	if m := reQuote.FindStringSubmatch(line); m != nil {
+		depth := min(strings.Count(m[1], ">"), MaxQuoteDepth)
+		c.emit(strings.Repeat(">", depth) + " " + c.inline(line[len(m[0]):]))
+		return
+	}
+	line = reTaskItem.ReplaceAllStringFunc(line, func(s string) string {
+		m := reTaskItem.FindStringSubmatch(s)
+		if m[2] == " " {
+			return m[1] + "☐ "
+		}
+		return m[1] + "☑ "
+	})
+	for i, part := range strings.Split(reBreak.ReplaceAllString(line, "\n"), "\n") {
+		if i > 0 {
+			part = strings.TrimLeft(part, " ")
+		}
+		c.emit(c.inline(part))
+	}
+}
+
+// stripComments 去除HTML注释，支持跨行注释。
+func (c *converter) stripComments(line string) string {
+	if c.comment {
+		i := strings.Index(line, "-->")
+		if i < 0 {
+			return ""
+		}
+		c.comment = false
+		line = line[i+3:]
+	}
+	line = reComment.ReplaceAllString(line, "")
+	if i := strings.Index(line, "<!--"); i >= 0 {
+		c.comment = true
+		line = line[:i]
+	}
+	return line
+}
+
+func (c *converter) noteID(id string) int {
+	n, ok := c.noteIDs[id]
+	if !ok {
+		c.notes = append(c.notes, "")
+		n = len(c.notes)
+		c.noteIDs[id] = n
+	}
+	return n
+}
+
+// inline 转换行内语法，行内代码中的内容保持不变。
+func (c *converter) inline(s string) string {
+	parts := strings.Split(s, "`")
+	for i := 0; i < len(parts); i += 2 {
+		parts[i] = c.inlineText(parts[i])
+	}
+	return strings.Join(parts, "`")
+}
+
+func (c *converter) inlineText(s string) string {
+	s = reAutolink.ReplaceAllString(s, "$1")
+	s = reBreak.ReplaceAllString(s, " ")
+	s = reTag.ReplaceAllStringFunc(s, func(m string) string {
+		if htmlTags[strings.ToLower(reTag.FindStringSubmatch(m)[1])] {
+			return ""
+		}
+		return m
+	})
+	s = reStrike.ReplaceAllString(s, "$1")
+	s = reFootnoteRef.ReplaceAllStringFunc(s, func(m string) string {
+		id := reFootnoteRef.FindStringSubmatch(m)[1]
+		return "[" + strconv.Itoa(c.noteID(id)) + "]"
+	})
+	s = reRefLink.ReplaceAllStringFunc(s, func(m string) string {
+		sub := reRefLink.FindStringSubmatch(m)
+		ref := sub[3]
+		if ref == "" {
+			ref = sub[2]
+		}
+		url, ok := c.refs[strings.ToLower(ref)]
+		if !ok {
+			return sub[2]
+		}
+		return sub[1] + "[" + sub[2] + "](" + url + ")"
+	})
+	s = reImage.ReplaceAllStringFunc(s, func(m string) string {
+		sub := reImage.FindStringSubmatch(m)
+		if strings.HasPrefix(sub[2], "http://") || strings.HasPrefix(sub[2], "https://") {
+			return "![" + sub[1] + "](" + sub[2] + ")"
+		}
+		return sub[1]
+	})
+	return s
+}
+
+// row 将表格行规范为首尾带竖线且列数与表头一致的形式，多出的单元格以转义的竖线并入最后一列。
+func (c *converter) row(cells []string) string {
+	cols := max(c.tableCols, 1)
+	if len(cells) > cols {
+		cells = append(cells[:cols-1:cols-1], strings.Join(cells[cols-1:], ` \| `))
+	}
+	var sb strings.Builder
+	sb.WriteByte('|')
+	for i := range cols {
+		cell := ""
+		if i < len(cells) {
+			cell = c.inline(reBreak.ReplaceAllString(cells[i], " "))
+		}
+		sb.WriteString(" " + cell + " |")
+	}
+	return sb.String()
+}
+
+// separator 按原对齐方式重建表格分隔行。
+func separator(cells []string, cols int) string {
+	var sb strings.Builder
+	sb.WriteByte('|')
+	for i := range cols {
+		sep := "---"
+		if i < len(cells) {
+			cell := strings.TrimSpace(cells[i])
+			left, right := strings.HasPrefix(cell, ":"), strings.HasSuffix(cell, ":")
+			switch {
+			case left && right:
+				sep = ":---:"
+			case left:
+				sep = ":---"
+			case right:
+				sep = "---:"
+			}
+		}
+		sb.WriteString(" " + sep + " |")
+	}
+	return sb.String()
+}
+
+// isTableRow 判断行是否包含未转义的竖线。
+func isTableRow(line string) bool {
+	return len(splitRow(line)) > 1 || strings.HasPrefix(strings.TrimSpace(line), "|")
+}
+
+// splitRow 按未转义的竖线拆分表格行，去除首尾竖线。
+func splitRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+	var cells []string
+	var cell strings.Builder
+	code := false
+	for i := 0; i < len(line); i++ {
+		switch ch := line[i]; {
+		case ch == '\\' && i+1 < len(line) && line[i+1] == '|':
+			cell.WriteString(`\|`)
+			i++
+		case ch == '`':
+			code = !code
+			cell.WriteByte(ch)
+		case ch == '|' && !code:
+			cells = append(cells, strings.TrimSpace(cell.String()))
+			cell.Reset()
+		default:
+			cell.WriteByte(ch)
+		}
+	}
+	return append(cells, strings.TrimSpace(cell.String()))
+}
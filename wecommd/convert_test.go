@@ -0,0 +1,76 @@
+package wecommd
+
+import "testing"
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "# 标题\n\n正文 **加粗**", "# 标题\n\n正文 **加粗**"},
+		{"deep heading", "#### 小节", "**小节**"},
+		{"setext underline", "标题\n===", "标题"},
+		{"html tags", "<div>a <span class=\"x\">b</span></div>", "a b"},
+		{"html break", "a<br>b<br/>c", "a\nb\nc"},
+		{"generic types", "Use Vec<String> and x<y", "Use Vec<String> and x<y"},
+		{"unknown tag", "<custom>x</custom>", "<custom>x</custom>"},
+		{"tag case", "<DIV>x</DIV>", "x"},
+		{"tag in code span", "`<div>`", "`<div>`"},
+		{"comment", "a<!-- hidden -->b", "ab"},
+		{"multiline comment", "a<!--\nhidden\n-->b", "a\n\nb"},
+		{"autolink", "<https://example.com>", "https://example.com"},
+		{"strike", "~~旧~~新", "旧新"},
+		{"task list", "- [ ] 待办\n- [x] 完成", "- ☐ 待办\n- ☑ 完成"},
+		{"quote depth", ">>>> 深", ">>> 深"},
+		{"footnote", "正文[^a]\n\n[^a]: 注释", "正文[1]\n\n\n---\n1. 注释"},
+		{"ref link", "[文档][doc]\n\n[doc]: https://example.com", "[文档](https://example.com)"},
+		{"local image", "![图](local.png)", "图"},
+		{"remote image", "![图](https://example.com/a.png)", "![图](https://example.com/a.png)"},
+		{"table", "a | b\n:-|-:\n1 | 2", "| a | b |\n| :--- | ---: |\n| 1 | 2 |"},
+		{"table short row", "| a | b |\n|---|---|\n| 1 |", "| a | b |\n| --- | --- |\n| 1 |  |"},
+		{"table extra cells", "| a | b |\n|---|---|\n| 1 | 2 | 3 | 4 |", "| a | b |\n| --- | --- |\n| 1 | 2 \\| 3 \\| 4 |"},
+		{"table end", "| a |\n|---|\n| 1 |\n\n后文", "| a |\n| --- |\n| 1 |\n\n后文"},
+		{"pipe without separator", "a | b\n正文", "a | b\n正文"},
+		{"tilde fence", "~~~py\nx = 1\n~~~", "```py\nx = 1\n```"},
+		{"long fence", "````md\n```go\nx\n```\n````", "```md\n    ```go\nx\n    ```\n```"},
+		{"fence keeps html", "```\n<div>x</div>\n```", "```\n<div>x</div>\n```"},
+		{"unclosed fence", "```go\nx", "```go\nx\n```"},
+		{"indented fence", "1. 步骤\n\n    ```sh\n    <cmd> | grep x\n    ```\n2. 下一步", "1. 步骤\n\n    ```sh\n    <cmd> | grep x\n    ```\n2. 下一步"},
+		{"unclosed indented fence", "- a\n  ```\n  x", "- a\n  ```\n  x\n  ```"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Convert(tt.in); got != tt.want {
+				t.Errorf("Convert(%q)\n got: %q\nwant: %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"heading and emphasis", "## 标题\n\n**粗** 和 *斜*", "标题\n\n粗 和 斜"},
+		{"link", "[文档](https://example.com)", "文档（https://example.com）"},
+		{"bare link", "[https://example.com](https://example.com)", "https://example.com"},
+		{"image", "![图](https://example.com/a.png) ![](https://example.com/b.png)", "[图片：图] [图片]"},
+		{"list and quote", "- a\n  - b\n> 引用", "• a\n  • b\n引用"},
+		{"rule", "---", "————————"},
+		{"code span", "运行 `go test`", "运行 go test"},
+		{"generic types", "Use Vec<String>", "Use Vec<String>"},
+		{"table", "| a | b |\n|---|---|\n| 1 | x\\|y |", "a  b\n1  x|y"},
+		{"fence", "```go\n**x** := `y`\n```", "**x** := `y`"},
+		{"indented fence", "- a\n\n    ```\n    `x`\n      y\n    ```", "• a\n\n`x`\n  y"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PlainText(tt.in); got != tt.want {
+				t.Errorf("PlainText(%q)\n got: %q\nwant: %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package wecommd
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	reLink      = regexp.MustCompile(`\[([^\]]*)\]\(([^)\s]*)(?:\s+"[^"]*")?\)`)
+	reEmphasis  = regexp.MustCompile(`(\*\*|__)(.+?)(\*\*|__)`)
+	reItalic    = regexp.MustCompile(`(^|[^*\w])[*_]([^*_\s][^*_]*?)[*_]([^*\w]|$)`)
+	reCodeSpan  = regexp.MustCompile("`([^`]*)`")
+	reListItem  = regexp.MustCompile(`^(\s*)[-*+]\s+`)
+	reRule      = regexp.MustCompile(`^ {0,3}(?:-{3,}|\*{3,}|_{3,})\s*$`)
+	reQuoteMark = regexp.MustCompile(`^ {0,3}(?:>\s?)+`)
+)
+
+// PlainText 将Markdown转换为纯文本，用于只能发送文本消息的场景：
+// 去除标题、强调、引用和代码块标记，链接改为“文字（链接）”，图片改为替代文字，
+// 表格改为以空格分隔的行。
+func PlainText(md string) string {
+	md = Convert(md)
+	var sb strings.Builder
+	fence, indent := false, ""
+	for line := range strings.Lines(md) {
+		line = strings.TrimRight(line, "\n")
+		if trimmed := strings.TrimLeft(line, " \t"); strings.HasPrefix(trimmed, "```") {
+			// Convert 输出的代码块标记可能带缩进，代码行去除与起始标记相同的缩进。
+			fence, indent = !fence, line[:len(line)-len(trimmed)]
+			continue
+		}
+		if fence {
+			sb.WriteString(strings.TrimPrefix(line, indent) + "\n")
+			continue
+		}
+		switch {
+		case reRule.MatchString(line):
+			sb.WriteString("————————\n")
+			continue
+		case reTableSep.MatchString(line) && strings.Contains(line, "-"):
+			continue
+		case strings.HasPrefix(line, "|"):
+			line = strings.Join(splitRow(line), "  ")
+		}
+		if m := reHeading.FindStringSubmatch(line); m != nil {
+			line = m[2]
+		}
+		line = reQuoteMark.ReplaceAllString(line, "")
+		line = reListItem.ReplaceAllString(line, "$1• ")
+		sb.WriteString(plainInline(line) + "\n")
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+func plainInline(s string) string {
+	s = reImage.ReplaceAllStringFunc(s, func(m string) string {
+		alt := reImage.FindStringSubmatch(m)[1]
+		if alt == "" {
+			return "[图片]"
+		}
+		return "[图片：" + alt + "]"
+	})
+	s = reLink.ReplaceAllStringFunc(s, func(m string) string {
+		sub := reLink.FindStringSubmatch(m)
+		if sub[1] == "" || sub[1] == sub[2] {
+			return sub[2]
+		}
+		return sub[1] + "（" + sub[2] + "）"
+	})
+	s = reCodeSpan.ReplaceAllString(s, "$1")
+	s = reEmphasis.ReplaceAllString(s, "$2")
+	s = reItalic.ReplaceAllString(s, "$1$2$3")
+	return strings.ReplaceAll(s, `\|`, "|")
+}
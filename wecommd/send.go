@@ -0,0 +1,23 @@
+package wecommd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-sphere/wecom-bot-api/wecomapi"
+)
+
+// SendChunks 依次将分段以Markdown主动回复发送，第i个分段发送到 callbacks[i] 的 response_url。
+// 每个 response_url 只能调用一次，因此需为每个分段提供一个回调；
+// 不足时先发送可发送的分段再返回错误。返回成功发送的分段数，遇到错误立即停止。
+func SendChunks(ctx context.Context, client *wecomapi.ActiveReplyClient, callbacks []*wecomapi.Callback, chunks []string) (int, error) {
+	for i, chunk := range chunks {
+		if i >= len(callbacks) {
+			return i, fmt.Errorf("wecommd: %d chunks but only %d callbacks", len(chunks), len(callbacks))
+		}
+		if err := client.SendFor(ctx, callbacks[i], wecomapi.NewMarkdownReply(chunk)); err != nil {
+			return i, fmt.Errorf("wecommd: send chunk %d/%d: %w", i+1, len(chunks), err)
+		}
+	}
+	return len(chunks), nil
+}
@@ -0,0 +1,263 @@
+package wecommd
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultBudget 单条Markdown消息的默认字节上限。
+const DefaultBudget = 20480
+
+// DefaultFooter 默认的分段页脚格式。
+const DefaultFooter = "(%d/%d)"
+
+// minBudget 分段的最小字节数，过小时无法容纳代码块和表格的首尾。
+const minBudget = 64
+
+type splitConfig struct {
+	footer string
+}
+
+// SplitOption 配置分段。
+type SplitOption func(*splitConfig)
+
+// WithFooter 在每个分段末尾追加页脚，format 包含两个 %d，分别为序号和总数；
+// format 为空时使用 DefaultFooter。只有一个分段时不追加页脚。
+func WithFooter(format string) SplitOption {
+	return func(c *splitConfig) {
+		if format == "" {
+			format = DefaultFooter
+		}
+		c.footer = format
+	}
+}
+
+type blockKind int
+
+const (
+	blockText blockKind = iota
+	blockHeading
+	blockFence
+	blockTable
+)
+
+type block struct {
+	kind  blockKind
+	lines []string
+}
+
+func (b block) String() string {
+	return strings.Join(b.lines, "\n")
+}
+
+// Split 按块边界将Markdown切分为不超过budget字节的分段。
+// 跨越切分点的代码块会被闭合并在下一段重新打开，表格会在下一段重复表头，
+// 标题不会单独留在分段末尾。
+func Split(md string, budget int, opts ...SplitOption) []string {
+	var cfg splitConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	budget = max(budget, minBudget)
+	limit := budget
+	if cfg.footer != "" {
+		limit -= len("\n\n") + len(fmt.Sprintf(cfg.footer, 999, 999))
+	}
+	chunks := pack(parseBlocks(md), max(limit, minBudget/2))
+	if cfg.footer != "" && len(chunks) > 1 {
+		for i := range chunks {
+			chunks[i] += "\n\n" + fmt.Sprintf(cfg.footer, i+1, len(chunks))
+		}
+	}
+	return chunks
+}
+
+// parseBlocks 将Markdown拆分为段落、标题、代码块和表格。
+func parseBlocks(md string) []block {
+	lines := strings.Split(strings.ReplaceAll(strings.TrimRight(md, "\n"), "\r\n", "\n"), "\n")
+	var blocks []block
+	var text []string
+	flushText := func() {
+		if len(text) > 0 {
+			blocks = append(blocks, block{kind: blockText, lines: text})
+			text = nil
+		}
+	}
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		switch {
+		case strings.TrimSpace(line) == "":
+			flushText()
+		case reFence.MatchString(line):
+			flushText()
+			marker := reFence.FindStringSubmatch(line)[2]
+			b := block{kind: blockFence, lines: []string{line}}
+			for i++; i < len(lines); i++ {
+				b.lines = append(b.lines, lines[i])
+				trimmed := strings.TrimSpace(lines[i])
+				if strings.HasPrefix(trimmed, marker) && strings.Trim(trimmed, marker[:1]) == "" {
+					break
+				}
+			}
+			blocks = append(blocks, b)
+		case isTableRow(line) && i+1 < len(lines) && reTableSep.MatchString(lines[i+1]) && strings.Contains(lines[i+1], "-"):
+			flushText()
+			b := block{kind: blockTable, lines: []string{line, lines[i+1]}}
+			for i += 2; i < len(lines) && isTableRow(lines[i]) && strings.TrimSpace(lines[i]) != ""; i++ {
+				b.lines = append(b.lines, lines[i])
+			}
+			i--
+			blocks = append(blocks, b)
+		case reHeading.MatchString(line):
+			flushText()
+			blocks = append(blocks, block{kind: blockHeading, lines: []string{line}})
+		default:
+			text = append(text, line)
+		}
+	}
+	flushText()
+	return blocks
+}
+
+// pack 将块依次装入不超过limit字节的分段。
+func pack(blocks []block, limit int) []string {
+	var chunks []string
+	var cur []string
+	size := 0
+	flush := func() {
+		if len(cur) == 0 {
+			return
+		}
+		// 标题不单独留在分段末尾。
+		var carry []string
+		if len(cur) > 1 && reHeading.MatchString(cur[len(cur)-1]) {
+			carry = cur[len(cur)-1:]
+			cur = cur[:len(cur)-1]
+		}
+		chunks = append(chunks, strings.Join(cur, "\n\n"))
+		cur = append([]string(nil), carry...)
+		size = 0
+		for _, s := range cur {
+			size += len(s)
+		}
+	}
+	add := func(s string) {
+		if len(cur) > 0 && size+len("\n\n")+len(s) > limit {
+			flush()
+		}
+		if len(cur) > 0 && size+len("\n\n")+len(s) > limit {
+			// 携带的标题与当前块无法同时放入，单独成段。
+			flush()
+		}
+		if len(cur) > 0 {
+			size += len("\n\n")
+		}
+		cur = append(cur, s)
+		size += len(s)
+	}
+	for _, b := range blocks {
+		s := b.String()
+		if len(s) <= limit {
+			add(s)
+			continue
+		}
+		// 需要切分的块与其前面的标题放在同一段。
+		heading := ""
+		if n := len(cur); n > 0 && reHeading.MatchString(cur[n-1]) {
+			heading, cur = cur[n-1], cur[:n-1]
+			size -= len(heading)
+			if len(cur) > 0 {
+				size -= len("\n\n")
+			}
+		}
+		pieces := splitBlock(b, limit-len(heading)-len("\n\n"))
+		if heading != "" && len(pieces) > 0 {
+			pieces[0] = heading + "\n\n" + pieces[0]
+		}
+		for _, piece := range pieces {
+			add(piece)
+		}
+	}
+	if len(cur) > 0 {
+		chunks = append(chunks, strings.Join(cur, "\n\n"))
+	}
+	return chunks
+}
+
+// splitBlock 将超出上限的块切分为多段：代码块闭合后重新打开，表格重复表头。
+func splitBlock(b block, limit int) []string {
+	var head []string
+	var tail string
+	body := b.lines
+	switch b.kind {
+	case blockFence:
+		m := reFence.FindStringSubmatch(b.lines[0])
+		head = []string{m[1] + "```" + strings.TrimSpace(m[3])}
+		tail = m[1] + "```"
+		body = b.lines[1:]
+		if n := len(body); n > 0 && strings.Trim(strings.TrimSpace(body[n-1]), m[2][:1]) == "" {
+			body = body[:n-1]
+		}
+	case blockTable:
+		head = b.lines[:2]
+		body = b.lines[2:]
+	}
+	overhead := 0
+	for _, h := range head {
+		overhead += len(h) + 1
+	}
+	if tail != "" {
+		overhead += len(tail) + 1
+	}
+	room := max(limit-overhead, 1)
+	var pieces []string
+	var cur []string
+	size := 0
+	flush := func() {
+		if len(cur) == 0 {
+			return
+		}
+		lines := append(append([]string(nil), head...), cur...)
+		if tail != "" {
+			lines = append(lines, tail)
+		}
+		pieces = append(pieces, strings.Join(lines, "\n"))
+		cur, size = nil, 0
+	}
+	for _, line := range body {
+		for _, part := range splitLine(line, room) {
+			if len(cur) > 0 && size+1+len(part) > room {
+				flush()
+			}
+			if len(cur) > 0 {
+				size++
+			}
+			cur = append(cur, part)
+			size += len(part)
+		}
+	}
+	flush()
+	return pieces
+}
+
+// splitLine 将超长的行切分为不超过n字节的片段，优先在空白或标点处切分。
+func splitLine(line string, n int) []string {
+	var parts []string
+	for len(line) > n {
+		cut := n
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		if i := strings.LastIndexAny(line[:cut], " \t，。；！？,.;!?"); i > cut/2 {
+			_, size := utf8.DecodeRuneInString(line[i:])
+			cut = i + size
+		}
+		if cut == 0 {
+			_, cut = utf8.DecodeRuneInString(line)
+		}
+		parts = append(parts, line[:cut])
+		line = line[cut:]
+	}
+	return append(parts, line)
+}
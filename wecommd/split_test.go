@@ -0,0 +1,130 @@
+package wecommd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func checkBudget(t *testing.T, chunks []string, budget int) {
+	t.Helper()
+	for i, c := range chunks {
+		if len(c) > budget {
+			t.Errorf("chunk %d is %d bytes, budget %d", i, len(c), budget)
+		}
+		if !utf8.ValidString(c) {
+			t.Errorf("chunk %d is not valid UTF-8", i)
+		}
+	}
+}
+
+func TestSplitShort(t *testing.T) {
+	md := "# 标题\n\n正文"
+	chunks := Split(md, DefaultBudget, WithFooter(""))
+	if len(chunks) != 1 || chunks[0] != md {
+		t.Errorf("Split = %q, want single unchanged chunk", chunks)
+	}
+}
+
+func TestSplitParagraphs(t *testing.T) {
+	paras := make([]string, 30)
+	for i := range paras {
+		paras[i] = fmt.Sprintf("段落%d：%s", i, strings.Repeat("内容", 10))
+	}
+	md := strings.Join(paras, "\n\n")
+	chunks := Split(md, 300)
+	if len(chunks) < 2 {
+		t.Fatalf("got %d chunks", len(chunks))
+	}
+	checkBudget(t, chunks, 300)
+	if got := strings.Join(chunks, "\n\n"); got != md {
+		t.Errorf("joined chunks differ from input")
+	}
+}
+
+func TestSplitFooter(t *testing.T) {
+	md := strings.Repeat("line of text\n\n", 100)
+	chunks := Split(md, 200, WithFooter(""))
+	checkBudget(t, chunks, 200)
+	for i, c := range chunks {
+		if want := fmt.Sprintf("\n\n(%d/%d)", i+1, len(chunks)); !strings.HasSuffix(c, want) {
+			t.Errorf("chunk %d = %q, want footer %q", i, c, want)
+		}
+	}
+}
+
+func TestSplitCodeFence(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("intro\n\n```go\n")
+	for i := range 40 {
+		fmt.Fprintf(&sb, "fmt.Println(%d)\n", i)
+	}
+	sb.WriteString("```\n\noutro")
+	chunks := Split(sb.String(), 200)
+	if len(chunks) < 3 {
+		t.Fatalf("got %d chunks", len(chunks))
+	}
+	checkBudget(t, chunks, 200)
+	for i, c := range chunks {
+		fences := 0
+		for line := range strings.Lines(c) {
+			if strings.HasPrefix(line, "```") {
+				fences++
+			}
+		}
+		if fences%2 != 0 {
+			t.Errorf("chunk %d has unbalanced fences:\n%s", i, c)
+		}
+		if strings.Contains(c, "fmt.Println") && !strings.Contains(c, "```go\n") {
+			t.Errorf("chunk %d does not reopen the go fence:\n%s", i, c)
+		}
+	}
+}
+
+func TestSplitTable(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("| 名称 | 数量 |\n| --- | ---: |\n")
+	for i := range 40 {
+		fmt.Fprintf(&sb, "| item%d | %d |\n", i, i)
+	}
+	chunks := Split(sb.String(), 200)
+	if len(chunks) < 2 {
+		t.Fatalf("got %d chunks", len(chunks))
+	}
+	checkBudget(t, chunks, 200)
+	rows := 0
+	for i, c := range chunks {
+		if !strings.HasPrefix(c, "| 名称 | 数量 |\n| --- | ---: |\n") {
+			t.Errorf("chunk %d lacks table header:\n%s", i, c)
+		}
+		rows += strings.Count(c, "| item")
+	}
+	if rows != 40 {
+		t.Errorf("rows across chunks = %d, want 40", rows)
+	}
+}
+
+func TestSplitHeadingNotLast(t *testing.T) {
+	var parts []string
+	for i := range 20 {
+		parts = append(parts, fmt.Sprintf("## 第%d节", i), strings.Repeat("正文", 20))
+	}
+	chunks := Split(strings.Join(parts, "\n\n"), 200)
+	checkBudget(t, chunks, 200)
+	for i, c := range chunks {
+		blocks := strings.Split(c, "\n\n")
+		if last := blocks[len(blocks)-1]; strings.HasPrefix(last, "#") {
+			t.Errorf("chunk %d ends with heading %q", i, last)
+		}
+	}
+}
+
+func TestSplitLongLine(t *testing.T) {
+	md := strings.Repeat("很长的一行没有换行", 100)
+	chunks := Split(md, 100)
+	checkBudget(t, chunks, 100)
+	if got := strings.Join(chunks, ""); got != md {
+		t.Errorf("joined chunks differ from input")
+	}
+}
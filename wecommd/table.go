@@ -0,0 +1,241 @@
+package wecommd
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Align 表格列的对齐方式。
+type Align int
+
+const (
+	AlignDefault Align = iota
+	AlignLeft
+	AlignCenter
+	AlignRight
+)
+
+type tableConfig struct {
+	aligns   []Align
+	maxWidth int
+	maxRows  int
+}
+
+// TableOption 配置表格渲染。
+type TableOption func(*tableConfig)
+
+// WithAlign 依次设置各列的对齐方式，AlignDefault 表示保留该列已有的对齐方式，
+// 如 TableFromStructs 由标签得到的对齐方式。
+func WithAlign(aligns ...Align) TableOption {
+	return func(c *tableConfig) {
+		if n := len(aligns) - len(c.aligns); n > 0 {
+			c.aligns = append(c.aligns, make([]Align, n)...)
+		}
+		for i, a := range aligns {
+			if a != AlignDefault {
+				c.aligns[i] = a
+			}
+		}
+	}
+}
+
+// WithMaxColumnWidth 限制单元格的最大字符数，超出部分以省略号结尾。
+func WithMaxColumnWidth(n int) TableOption {
+	return func(c *tableConfig) {
+		c.maxWidth = n
+	}
+}
+
+// WithMaxRows 限制表格的最大行数（不含表头），超出时在表格下方注明省略的行数。
+func WithMaxRows(n int) TableOption {
+	return func(c *tableConfig) {
+		c.maxRows = n
+	}
+}
+
+// Table 将表头和数据行渲染为Markdown表格，行的列数不足时补空，超出时截断。
+func Table(header []string, rows [][]string, opts ...TableOption) string {
+	var cfg tableConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg.render(header, rows)
+}
+
+func (c *tableConfig) render(header []string, rows [][]string) string {
+	if len(header) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	c.writeRow(&sb, header)
+	sb.WriteByte('|')
+	for i := range header {
+		var align Align
+		if i < len(c.aligns) {
+			align = c.aligns[i]
+		}
+		sb.WriteString(" " + alignMarker(align) + " |")
+	}
+	sb.WriteByte('\n')
+	shown := rows
+	if c.maxRows > 0 && len(rows) > c.maxRows {
+		shown = rows[:c.maxRows]
+	}
+	for _, row := range shown {
+		cells := make([]string, len(header))
+		copy(cells, row)
+		c.writeRow(&sb, cells)
+	}
+	if len(shown) < len(rows) {
+		fmt.Fprintf(&sb, "\n*共%d行，已省略%d行*\n", len(rows), len(rows)-len(shown))
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+func (c *tableConfig) writeRow(sb *strings.Builder, cells []string) {
+	sb.WriteByte('|')
+	for _, cell := range cells {
+		sb.WriteString(" " + c.cell(cell) + " |")
+	}
+	sb.WriteByte('\n')
+}
+
+// cell 转义单元格中的竖线和换行，并按最大字符数截断。
+func (c *tableConfig) cell(s string) string {
+	s = strings.Join(strings.Fields(strings.ReplaceAll(s, "\r\n", "\n")), " ")
+	if c.maxWidth > 0 && utf8.RuneCountInString(s) > c.maxWidth {
+		runes := []rune(s)
+		s = string(runes[:max(c.maxWidth-1, 0)]) + "…"
+	}
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+func alignMarker(a Align) string {
+	switch a {
+	case AlignLeft:
+		return ":---"
+	case AlignCenter:
+		return ":---:"
+	case AlignRight:
+		return "---:"
+	}
+	return "---"
+}
+
+// TableFromCSV 读取CSV并渲染为Markdown表格，第一行为表头。
+func TableFromCSV(r io.Reader, opts ...TableOption) (string, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	records, err := cr.ReadAll()
+	if err != nil {
+		return "", fmt.Errorf("wecommd: read csv: %w", err)
+	}
+	if len(records) == 0 {
+		return "", errors.New("wecommd: empty csv")
+	}
+	return Table(records[0], records[1:], opts...), nil
+}
+
+// TableFromStructs 将结构体切片渲染为Markdown表格。
+// 列名和对齐方式由字段的 md 标签指定，如 `md:"金额,right"`；标签为 "-" 的字段被忽略，
+// 未导出字段被忽略，未指定列名时使用字段名，数值字段默认右对齐。
+// WithAlign 中非 AlignDefault 的对齐方式按列覆盖标签。
+func TableFromStructs(v any, opts ...TableOption) (string, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return "", fmt.Errorf("wecommd: TableFromStructs needs a slice, got %T", v)
+	}
+	elem := rv.Type().Elem()
+	for elem.Kind() == reflect.Pointer {
+		elem = elem.Elem()
+	}
+	if elem.Kind() != reflect.Struct {
+		return "", fmt.Errorf("wecommd: TableFromStructs needs a slice of structs, got %T", v)
+	}
+	var header []string
+	var aligns []Align
+	var fields []int
+	for i := 0; i < elem.NumField(); i++ {
+		f := elem.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		tag := f.Tag.Get("md")
+		if tag == "-" {
+			continue
+		}
+		name, opt, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = f.Name
+		}
+		header = append(header, name)
+		aligns = append(aligns, parseAlign(opt, f.Type))
+		fields = append(fields, i)
+	}
+	rows := make([][]string, 0, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		item := rv.Index(i)
+		for item.Kind() == reflect.Pointer {
+			if item.IsNil() {
+				break
+			}
+			item = item.Elem()
+		}
+		row := make([]string, len(fields))
+		if item.Kind() == reflect.Struct {
+			for j, idx := range fields {
+				row[j] = formatValue(item.Field(idx))
+			}
+		}
+		rows = append(rows, row)
+	}
+	cfg := tableConfig{aligns: aligns}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg.render(header, rows), nil
+}
+
+func parseAlign(opt string, t reflect.Type) Align {
+	switch opt {
+	case "left":
+		return AlignLeft
+	case "center":
+		return AlignCenter
+	case "right":
+		return AlignRight
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return AlignRight
+	}
+	return AlignDefault
+}
+
+func formatValue(v reflect.Value) string {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if s, ok := v.Interface().(fmt.Stringer); ok {
+		return s.String()
+	}
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits())
+	}
+	return fmt.Sprint(v.Interface())
+}
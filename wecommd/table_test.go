@@ -0,0 +1,107 @@
+package wecommd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestTable(t *testing.T) {
+	tests := []struct {
+		name   string
+		header []string
+		rows   [][]string
+		opts   []TableOption
+		want   string
+	}{
+		{"empty header", nil, [][]string{{"1"}}, nil, ""},
+		{"basic", []string{"a", "b"}, [][]string{{"1", "2"}},
+			nil, "| a | b |\n| --- | --- |\n| 1 | 2 |"},
+		{"pad and cut rows", []string{"a", "b"}, [][]string{{"1"}, {"1", "2", "3"}},
+			nil, "| a | b |\n| --- | --- |\n| 1 |  |\n| 1 | 2 |"},
+		{"escape", []string{"a"}, [][]string{{"x|y\r\nz  w"}},
+			nil, "| a |\n| --- |\n| x\\|y z w |"},
+		{"align", []string{"a", "b", "c", "d"}, nil,
+			[]TableOption{WithAlign(AlignLeft, AlignCenter, AlignRight)}, "| a | b | c | d |\n| :--- | :---: | ---: | --- |"},
+		{"max width", []string{"名称"}, [][]string{{"一二三四五"}},
+			[]TableOption{WithMaxColumnWidth(3)}, "| 名称 |\n| --- |\n| 一二… |"},
+		{"max rows", []string{"n"}, [][]string{{"1"}, {"2"}, {"3"}},
+			[]TableOption{WithMaxRows(2)}, "| n |\n| --- |\n| 1 |\n| 2 |\n\n*共3行，已省略1行*"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Table(tt.header, tt.rows, tt.opts...); got != tt.want {
+				t.Errorf("Table\n got: %q\nwant: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTableFromCSV(t *testing.T) {
+	got, err := TableFromCSV(strings.NewReader("名称,数量\n\"a,b\",1\nc\n"), WithAlign(AlignDefault, AlignRight))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "| 名称 | 数量 |\n| --- | ---: |\n| a,b | 1 |\n| c |  |"; got != want {
+		t.Errorf("TableFromCSV\n got: %q\nwant: %q", got, want)
+	}
+	if _, err := TableFromCSV(strings.NewReader("")); err == nil {
+		t.Error("empty csv accepted")
+	}
+	if _, err := TableFromCSV(strings.NewReader("a\n\"b")); err == nil {
+		t.Error("malformed csv accepted")
+	}
+}
+
+type status int
+
+func (s status) String() string { return fmt.Sprintf("S%d", int(s)) }
+
+type order struct {
+	ID     string  `md:"编号"`
+	Amount float64 `md:"金额"`
+	Count  *int
+	Note   string `md:",center"`
+	State  status `md:"状态,left"`
+	Secret string `md:"-"`
+	hidden string
+}
+
+func TestTableFromStructs(t *testing.T) {
+	n := 3
+	orders := []*order{
+		{ID: "A1", Amount: 12.5, Count: &n, Note: "x", State: 1, Secret: "s", hidden: "h"},
+		nil,
+		{ID: "A2", Amount: 3},
+	}
+	tests := []struct {
+		name string
+		opts []TableOption
+		sep  string
+	}{
+		{"tag aligns", nil, "| --- | ---: | ---: | :---: | :--- |"},
+		{"merge per column", []TableOption{WithAlign(AlignDefault, AlignLeft)}, "| --- | :--- | ---: | :---: | :--- |"},
+		{"extra columns", []TableOption{WithAlign(AlignRight, AlignDefault, AlignDefault, AlignDefault, AlignDefault, AlignCenter)}, "| ---: | ---: | ---: | :---: | :--- |"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := TableFromStructs(orders, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := "| 编号 | 金额 | Count | Note | 状态 |\n" + tt.sep + "\n" +
+				"| A1 | 12.5 | 3 | x | S1 |\n" +
+				"|  |  |  |  |  |\n" +
+				"| A2 | 3 |  |  | S0 |"
+			if got != want {
+				t.Errorf("TableFromStructs\n got: %q\nwant: %q", got, want)
+			}
+		})
+	}
+
+	for _, v := range []any{order{}, []int{1}, nil} {
+		if _, err := TableFromStructs(v); err == nil {
+			t.Errorf("TableFromStructs(%T) accepted", v)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package wecommd
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Writer 将流式输出的Markdown逐行转换后写入下游，如 wecomapi.StreamWriter。
+// 代码块内未完成的行会先行写出；代码块外的行在换行后才能确定是否为表头等语法，
+// 因此在换行后转换写出。结束时须调用 Close 输出剩余内容、闭合未结束的代码块并追加脚注。
+type Writer struct {
+	mu      sync.Mutex
+	w       io.StringWriter
+	c       *converter
+	out     strings.Builder
+	line    strings.Builder // 当前未完成的行
+	emitted int             // 当前行已写出的字节数
+}
+
+// NewWriter 创建转换写入器。
+func NewWriter(w io.StringWriter) *Writer {
+	wr := &Writer{w: w, c: newConverter()}
+	wr.c.out = &wr.out
+	return wr
+}
+
+// Write 写入Markdown增量内容。
+func (w *Writer) Write(p []byte) (int, error) {
+	if _, err := w.WriteString(string(p)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// WriteString 写入Markdown增量内容。
+func (w *Writer) WriteString(s string) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	n := len(s)
+	for {
+		i := strings.IndexByte(s, '\n')
+		if i < 0 {
+			break
+		}
+		w.line.WriteString(s[:i])
+		w.feedLine()
+		s = s[i+1:]
+	}
+	w.line.WriteString(s)
+	w.partial()
+	return n, w.drain()
+}
+
+// Close 输出剩余内容，闭合未结束的代码块并追加脚注。
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.line.Len() > 0 {
+		w.feedLine()
+	}
+	w.c.flush()
+	return w.drain()
+}
+
+func (w *Writer) feedLine() {
+	line := strings.TrimRight(w.line.String(), "\r")
+	if w.emitted > 0 {
+		// 已写出前缀的行必定是代码块内的普通行。
+		w.c.emit(line[w.emitted:])
+	} else {
+		w.c.feed(line)
+	}
+	w.line.Reset()
+	w.emitted = 0
+}
+
+// partial 写出代码块内未完成的行。可能是闭合标记的行在换行前暂不写出。
+func (w *Writer) partial() {
+	line := w.line.String()
+	if w.c.fence == "" {
+		return
+	}
+	if rest := strings.TrimLeft(line, " \t"); rest == "" || strings.ContainsAny(rest[:1], "`~") {
+		return
+	}
+	// 末尾可能是被截断的UTF-8字符。
+	end := len(line)
+	i := end - 1
+	for i > w.emitted && !utf8.RuneStart(line[i]) {
+		i--
+	}
+	if !utf8.FullRuneInString(line[i:end]) {
+		end = i
+	}
+	if end <= w.emitted {
+		return
+	}
+	w.out.WriteString(line[w.emitted:end])
+	w.emitted = end
+}
+
+func (w *Writer) drain() error {
+	if w.out.Len() == 0 {
+		return nil
+	}
+	s := w.out.String()
+	w.out.Reset()
+	_, err := w.w.WriteString(s)
+	return err
+}
@@ -0,0 +1,114 @@
+package wecommd
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// recorder 记录每次写入下游的内容。
+type recorder struct {
+	writes []string
+}
+
+func (r *recorder) WriteString(s string) (int, error) {
+	r.writes = append(r.writes, s)
+	return len(s), nil
+}
+
+func (r *recorder) String() string {
+	return strings.Join(r.writes, "")
+}
+
+func writeChunks(t *testing.T, chunks []string) *recorder {
+	t.Helper()
+	var r recorder
+	w := NewWriter(&r)
+	for _, c := range chunks {
+		if _, err := w.WriteString(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return &r
+}
+
+var writerInputs = []struct {
+	name string
+	in   string
+}{
+	{"paragraphs", "# 标题\n\n第一段 **加粗** 和 <span>标签</span>\n第二段 Vec<String>\n"},
+	{"table", "这是一个足够长的段落，用于确认表头不会被提前写出 | 第二列\n---|---\n1 | 2 | 3\n"},
+	{"fence", "前文\n\n~~~go\nfmt.Println(\"<b>\")\n~~~\n后文"},
+	{"long fence", "````\n```\ninner\n```\n````\n"},
+	{"indented fence", "1. 步骤\n\n    ```sh\n    echo 中文 | cat\n    ```\n2. 完成\n"},
+	{"unclosed fence", "```\n未闭合的代码"},
+	{"footnote and comment", "正文[^1]<!--\n隐藏\n-->结尾\n\n[^1]: 注释\n"},
+	{"crlf", "a | b\r\n-|-\r\n1 | 2\r\n"},
+}
+
+func TestWriterMatchesConvert(t *testing.T) {
+	for _, tt := range writerInputs {
+		want := Convert(tt.in)
+		t.Run(tt.name+"/whole", func(t *testing.T) {
+			if got := strings.TrimRight(writeChunks(t, []string{tt.in}).String(), "\n"); got != want {
+				t.Errorf("got %q, want %q", got, want)
+			}
+		})
+		t.Run(tt.name+"/bytes", func(t *testing.T) {
+			chunks := make([]string, len(tt.in))
+			for i := range len(tt.in) {
+				chunks[i] = tt.in[i : i+1]
+			}
+			r := writeChunks(t, chunks)
+			if got := strings.TrimRight(r.String(), "\n"); got != want {
+				t.Errorf("got %q, want %q", got, want)
+			}
+			for i, s := range r.writes {
+				if !utf8.ValidString(s) {
+					t.Errorf("write %d splits a UTF-8 character: %q", i, s)
+				}
+			}
+		})
+		t.Run(tt.name+"/splits", func(t *testing.T) {
+			for i := 1; i < len(tt.in); i++ {
+				got := strings.TrimRight(writeChunks(t, []string{tt.in[:i], tt.in[i:]}).String(), "\n")
+				if got != want {
+					t.Fatalf("split at %d: got %q, want %q", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestWriterPartialFenceLine(t *testing.T) {
+	var r recorder
+	w := NewWriter(&r)
+	_, _ = w.WriteString("```\nfmt.Println(")
+	if got := r.String(); got != "```\nfmt.Println(" {
+		t.Errorf("code line not streamed: %q", got)
+	}
+	_, _ = w.WriteString("1)\n``")
+	if got := r.String(); got != "```\nfmt.Println(1)\n" {
+		t.Errorf("possible closing fence written early: %q", got)
+	}
+	_, _ = w.WriteString("`\n")
+	if got := r.String(); got != "```\nfmt.Println(1)\n```\n" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestWriterHoldsParagraphLine(t *testing.T) {
+	var r recorder
+	w := NewWriter(&r)
+	_, _ = w.WriteString("这是一个足够长、以普通文字开头的段落，后面才会出现竖线")
+	if got := r.String(); got != "" {
+		t.Errorf("unterminated line written before it can be classified: %q", got)
+	}
+	_, _ = w.WriteString(" | b\n|-|-|\n")
+	if got := r.String(); got != "| 这是一个足够长、以普通文字开头的段落，后面才会出现竖线 | b |\n| --- | --- |\n" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,130 @@
+package wecompoll
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/go-sphere/wecom-bot-api/wecomapi"
+)
+
+// ErrPollClosed 投票已结束，不再接受作答。
+var ErrPollClosed = errors.New("wecompoll: poll closed")
+
+// Manager 创建投票、记录作答并统计结果。
+type Manager struct {
+	store Store
+	now   func() time.Time
+}
+
+// NewManager 创建投票管理器。
+func NewManager(store Store) *Manager {
+	return &Manager{store: store, now: time.Now}
+}
+
+// Create 保存投票并返回待发送的投票卡片，CreatedAt为零值时设置为当前时间。
+func (m *Manager) Create(ctx context.Context, p *Poll) (*wecomapi.TemplateCard, error) {
+	card, err := p.Card()
+	if err != nil {
+		return nil, err
+	}
+	if p.CreatedAt.IsZero() {
+		p.CreatedAt = m.now()
+	}
+	if err := m.store.SavePoll(ctx, p); err != nil {
+		return nil, err
+	}
+	return card, nil
+}
+
+// Record 记录用户的作答，替换该用户之前的作答，忽略不属于投票的问题和选项。
+func (m *Manager) Record(ctx context.Context, userID string, ev *wecomapi.TemplateCardEvent) (*Poll, error) {
+	p, err := m.store.LoadPoll(ctx, ev.TaskID)
+	if err != nil {
+		return nil, err
+	}
+	if p.Closed {
+		return p, ErrPollClosed
+	}
+	selections := make(map[string][]string)
+	for key, ids := range ev.Selections() {
+		q, ok := p.question(key)
+		if !ok {
+			continue
+		}
+		valid := slices.DeleteFunc(slices.Clone(ids), func(id string) bool {
+			return !slices.ContainsFunc(q.Options, func(o Option) bool { return o.ID == id })
+		})
+		if !p.Multi && len(valid) > 1 {
+			valid = valid[:1]
+		}
+		if len(valid) > 0 {
+			selections[key] = valid
+		}
+	}
+	if len(selections) == 0 {
+		return p, fmt.Errorf("wecompoll: no valid selection for poll %s", p.ID)
+	}
+	a := &Answer{UserID: userID, Selections: selections, AnsweredAt: m.now()}
+	if err := m.store.SaveAnswer(ctx, p.ID, a); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// HandleEvent 记录卡片事件中的作答，并为作答用户回复已提交状态的卡片，可直接注册到 wecomapi.Router。
+func (m *Manager) HandleEvent(ctx context.Context, cb *wecomapi.Callback) (*wecomapi.PassiveReply, error) {
+	if cb.Event == nil || cb.Event.TemplateCardEvent == nil {
+		return nil, errors.New("wecompoll: callback is not a template card event")
+	}
+	ev := cb.Event.TemplateCardEvent
+	p, err := m.Record(ctx, cb.From.UserID, ev)
+	if errors.Is(err, ErrPollClosed) {
+		res, err := m.Tally(ctx, p.ID)
+		if err != nil {
+			return nil, err
+		}
+		card, err := res.Card()
+		if err != nil {
+			return nil, err
+		}
+		return wecomapi.NewUpdateTemplateCardReply([]string{cb.From.UserID}, card), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	card, err := p.Card()
+	if err != nil {
+		return nil, err
+	}
+	return wecomapi.NewUpdatedCardReply([]string{cb.From.UserID}, card, ev), nil
+}
+
+// Tally 统计投票的当前结果。
+func (m *Manager) Tally(ctx context.Context, pollID string) (*Result, error) {
+	p, err := m.store.LoadPoll(ctx, pollID)
+	if err != nil {
+		return nil, err
+	}
+	answers, err := m.store.Answers(ctx, pollID)
+	if err != nil {
+		return nil, err
+	}
+	return NewResult(p, answers), nil
+}
+
+// Close 结束投票并返回最终结果，之后的作答将收到结果卡片。
+func (m *Manager) Close(ctx context.Context, pollID string) (*Result, error) {
+	p, err := m.store.LoadPoll(ctx, pollID)
+	if err != nil {
+		return nil, err
+	}
+	closed := *p
+	closed.Closed = true
+	if err := m.store.SavePoll(ctx, &closed); err != nil {
+		return nil, err
+	}
+	return m.Tally(ctx, pollID)
+}
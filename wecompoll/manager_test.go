@@ -0,0 +1,182 @@
+package wecompoll
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-sphere/wecom-bot-api/wecomapi"
+)
+
+func newTestManager(now *time.Time) *Manager {
+	m := NewManager(NewMemoryStore())
+	m.now = func() time.Time { return *now }
+	return m
+}
+
+func voteEvent(pollID string, selections map[string][]string) *wecomapi.TemplateCardEvent {
+	ev := &wecomapi.TemplateCardEvent{TaskID: pollID, EventKey: SubmitKey, SelectedItems: &wecomapi.SelectedItems{}}
+	for _, key := range slices.Sorted(maps.Keys(selections)) {
+		ev.SelectedItems.SelectedItem = append(ev.SelectedItems.SelectedItem, wecomapi.SelectedItem{
+			QuestionKey: key,
+			OptionIDs:   wecomapi.OptionIDs{OptionID: selections[key]},
+		})
+	}
+	return ev
+}
+
+func counts(q QuestionTally) []int {
+	n := make([]int, len(q.Options))
+	for i, o := range q.Options {
+		n[i] = o.Count
+	}
+	return n
+}
+
+func TestManagerVote(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	m := newTestManager(&now)
+	p := NewPoll("lunch", "午饭吃什么", false, "面", "饭", "饺子")
+	card, err := m.Create(ctx, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.CreatedAt.Equal(now) {
+		t.Errorf("CreatedAt = %v, want manager clock %v", p.CreatedAt, now)
+	}
+	if card.CardType != wecomapi.TemplateCardTypeVoteInteraction || card.TaskID != "lunch" || card.Checkbox.Mode != wecomapi.CheckboxModeSingle {
+		t.Errorf("card = %+v", card)
+	}
+
+	tests := []struct {
+		user       string
+		selections map[string][]string
+		wantErr    bool
+		want       []int
+	}{
+		{"u1", map[string][]string{"q1": {"opt1"}}, false, []int{1, 0, 0}},
+		{"u2", map[string][]string{"q1": {"opt2", "opt3"}}, false, []int{1, 1, 0}}, // 单选只保留第一项
+		{"u3", map[string][]string{"q1": {"bogus"}, "q9": {"opt1"}}, true, []int{1, 1, 0}},
+		{"u1", map[string][]string{"q1": {"opt3"}}, false, []int{0, 1, 1}}, // 再次作答替换之前的选择
+	}
+	for i, tt := range tests {
+		now = now.Add(time.Minute)
+		_, err := m.Record(ctx, tt.user, voteEvent("lunch", tt.selections))
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("step %d: Record = %v, wantErr %v", i, err, tt.wantErr)
+		}
+		res, err := m.Tally(ctx, "lunch")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := counts(res.Questions[0]); !slices.Equal(got, tt.want) {
+			t.Errorf("step %d: counts = %v, want %v", i, got, tt.want)
+		}
+	}
+
+	res, _ := m.Tally(ctx, "lunch")
+	if res.Voters != 2 || res.Questions[0].Respondents != 2 || res.Questions[0].Options[1].Percent != 50 {
+		t.Errorf("result = %+v", res)
+	}
+	if last := res.Answers[len(res.Answers)-1]; last.UserID != "u1" || !last.AnsweredAt.Equal(now) {
+		t.Errorf("latest answer = %+v", last)
+	}
+}
+
+func TestManagerMultiQuestion(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	m := newTestManager(&now)
+	p := &Poll{ID: "survey", Title: "调查", Questions: []Question{
+		{Key: "city", Title: "城市", Options: []Option{{ID: "bj", Text: "北京"}, {ID: "sh", Text: "上海"}}},
+		{Key: "team", Title: "团队", Options: []Option{{ID: "rd", Text: "研发"}, {ID: "op", Text: "运营"}}},
+	}}
+	card, err := m.Create(ctx, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if card.CardType != wecomapi.TemplateCardTypeMultipleInteraction || len(card.SelectList) != 2 {
+		t.Fatalf("card = %+v", card)
+	}
+	if _, err := m.Record(ctx, "u1", voteEvent("survey", map[string][]string{"city": {"sh"}, "team": {"rd"}})); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Record(ctx, "u2", voteEvent("survey", map[string][]string{"city": {"sh"}})); err != nil {
+		t.Fatal(err)
+	}
+	res, _ := m.Tally(ctx, "survey")
+	if got := counts(res.Questions[0]); !slices.Equal(got, []int{0, 2}) {
+		t.Errorf("city = %v", got)
+	}
+	if q := res.Questions[1]; q.Respondents != 1 || !slices.Equal(counts(q), []int{1, 0}) {
+		t.Errorf("team = %+v", q)
+	}
+}
+
+func TestManagerClose(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	m := newTestManager(&now)
+	p := NewPoll("p", "投票", true, "甲", "乙")
+	if _, err := m.Create(ctx, p); err != nil {
+		t.Fatal(err)
+	}
+	cb := &wecomapi.Callback{
+		From:  wecomapi.From{UserID: "u1"},
+		Event: &wecomapi.Event{EventType: wecomapi.EventTypeTemplateCard, TemplateCardEvent: voteEvent("p", map[string][]string{"q1": {"opt1", "opt2"}})},
+	}
+	reply, err := m.HandleEvent(ctx, cb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	updated := reply.TemplateCard
+	if updated == nil || !updated.Checkbox.Disable || !updated.Checkbox.OptionList[1].IsChecked || updated.SubmitButton.Text != wecomapi.DefaultSubmittedText {
+		t.Errorf("submitted card = %+v", updated)
+	}
+
+	res, err := m.Close(ctx, "p")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Poll.Closed || !slices.Equal(counts(res.Questions[0]), []int{1, 1}) {
+		t.Errorf("close result = %+v", res)
+	}
+	if _, err := m.Record(ctx, "u2", voteEvent("p", map[string][]string{"q1": {"opt1"}})); !errors.Is(err, ErrPollClosed) {
+		t.Errorf("Record after close = %v, want ErrPollClosed", err)
+	}
+
+	cb.From.UserID = "u2"
+	reply, err = m.HandleEvent(ctx, cb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	final := reply.TemplateCard
+	if final.SubmitButton.Text != "已结束" || final.Checkbox.OptionList[0].Text != "甲 (1)" || !strings.Contains(final.MainTitle.Desc, "1 人") {
+		t.Errorf("result card = %+v", final)
+	}
+	if res, _ := m.Tally(ctx, "p"); res.Voters != 1 {
+		t.Errorf("vote after close was recorded: %d voters", res.Voters)
+	}
+}
+
+func TestResultCardValidates(t *testing.T) {
+	p := NewPoll("p", "投票", false, strings.Repeat("长", 20))
+	res := NewResult(p, nil)
+	card, err := res.Card()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := card.Validate(); err != nil {
+		t.Errorf("result card fails Validate: %v", err)
+	}
+
+	p.Questions[0].Options[0].ID = ""
+	if _, err := NewResult(p, nil).Card(); err == nil {
+		t.Error("invalid poll produced a result card")
+	}
+}
@@ -0,0 +1,105 @@
+// Package wecompoll 基于投票选择和多项选择模板卡片的投票与统计。
+package wecompoll
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-sphere/wecom-bot-api/wecomapi"
+)
+
+// SubmitKey 投票卡片提交按钮的key。
+const SubmitKey = "poll_submit"
+
+// Option 投票选项。
+type Option struct {
+	ID   string `json:"id"`   // 选项ID，最长128字节
+	Text string `json:"text"` // 选项文案
+}
+
+// Question 投票问题。
+type Question struct {
+	Key     string   `json:"key"`             // 问题key，对应卡片中的question_key
+	Title   string   `json:"title,omitempty"` // 问题标题
+	Options []Option `json:"options"`         // 选项列表
+}
+
+// Poll 一次投票。
+// 只有一个问题时使用投票选择卡片（vote_interaction），支持多选；
+// 多个问题时使用多项选择卡片（multiple_interaction），每题单选，最多3题。
+type Poll struct {
+	ID        string     `json:"id"`         // 投票ID，同时作为卡片TaskID
+	Title     string     `json:"title"`      // 卡片标题
+	Desc      string     `json:"desc"`       // 卡片标题辅助信息
+	Questions []Question `json:"questions"`  // 问题列表
+	Multi     bool       `json:"multi"`      // 单个问题时是否允许多选
+	CreatedAt time.Time  `json:"created_at"` // 创建时间
+	Closed    bool       `json:"closed"`     // 是否已结束
+}
+
+// NewPoll 创建单个问题的投票，选项ID按顺序生成为opt1、opt2……
+// CreatedAt 由 Manager.Create 按管理器的时钟设置。
+func NewPoll(id, question string, multi bool, options ...string) *Poll {
+	opts := make([]Option, len(options))
+	for i, text := range options {
+		opts[i] = Option{ID: fmt.Sprintf("opt%d", i+1), Text: text}
+	}
+	return &Poll{
+		ID:        id,
+		Title:     question,
+		Questions: []Question{{Key: "q1", Options: opts}},
+		Multi:     multi,
+	}
+}
+
+// CardType 返回投票使用的卡片类型。
+func (p *Poll) CardType() wecomapi.TemplateCardType {
+	if len(p.Questions) == 1 {
+		return wecomapi.TemplateCardTypeVoteInteraction
+	}
+	return wecomapi.TemplateCardTypeMultipleInteraction
+}
+
+// Card 生成投票卡片。
+func (p *Poll) Card() (*wecomapi.TemplateCard, error) {
+	if len(p.Questions) == 0 {
+		return nil, errors.New("wecompoll: poll has no questions")
+	}
+	if p.CardType() == wecomapi.TemplateCardTypeVoteInteraction {
+		q := p.Questions[0]
+		mode := wecomapi.CheckboxModeSingle
+		if p.Multi {
+			mode = wecomapi.CheckboxModeMulti
+		}
+		options := make([]wecomapi.CheckboxOption, len(q.Options))
+		for i, o := range q.Options {
+			options[i] = wecomapi.NewCheckboxOption(o.ID, o.Text, false)
+		}
+		return wecomapi.NewVoteInteractionCard().
+			MainTitle(p.Title, p.Desc).
+			Checkbox(q.Key, mode, options...).
+			SubmitButton("提交", SubmitKey).
+			TaskID(p.ID).
+			Build()
+	}
+	b := wecomapi.NewMultipleInteractionCard().MainTitle(p.Title, p.Desc)
+	for _, q := range p.Questions {
+		options := make([]wecomapi.SelectOption, len(q.Options))
+		for i, o := range q.Options {
+			options[i] = wecomapi.NewSelectOption(o.ID, o.Text)
+		}
+		b.Select(q.Key, q.Title, options...)
+	}
+	return b.SubmitButton("提交", SubmitKey).TaskID(p.ID).Build()
+}
+
+// question 按key查找问题。
+func (p *Poll) question(key string) (*Question, bool) {
+	for i := range p.Questions {
+		if p.Questions[i].Key == key {
+			return &p.Questions[i], true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,173 @@
+package wecompoll
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sphere/wecom-bot-api/wecomapi"
+)
+
+// OptionCount 单个选项的票数。
+type OptionCount struct {
+	Option
+	Count   int     `json:"count"`   // 票数
+	Percent float64 `json:"percent"` // 占该问题作答人数的百分比
+}
+
+// QuestionTally 单个问题的统计。
+type QuestionTally struct {
+	Key         string        `json:"key"`
+	Title       string        `json:"title,omitempty"`
+	Respondents int           `json:"respondents"` // 作答人数
+	Options     []OptionCount `json:"options"`
+}
+
+// Result 投票统计结果。
+type Result struct {
+	Poll      *Poll           `json:"poll"`
+	Voters    int             `json:"voters"` // 参与人数
+	Questions []QuestionTally `json:"questions"`
+	Answers   []*Answer       `json:"answers"`
+}
+
+// NewResult 根据作答计算统计结果。
+func NewResult(p *Poll, answers []*Answer) *Result {
+	r := &Result{Poll: p, Voters: len(answers), Answers: answers}
+	for _, q := range p.Questions {
+		qt := QuestionTally{Key: q.Key, Title: q.Title, Options: make([]OptionCount, len(q.Options))}
+		index := make(map[string]int, len(q.Options))
+		for i, o := range q.Options {
+			qt.Options[i].Option = o
+			index[o.ID] = i
+		}
+		for _, a := range answers {
+			ids := a.Selections[q.Key]
+			if len(ids) > 0 {
+				qt.Respondents++
+			}
+			for _, id := range ids {
+				if i, ok := index[id]; ok {
+					qt.Options[i].Count++
+				}
+			}
+		}
+		if qt.Respondents > 0 {
+			for i := range qt.Options {
+				qt.Options[i].Percent = float64(qt.Options[i].Count) * 100 / float64(qt.Respondents)
+			}
+		}
+		r.Questions = append(r.Questions, qt)
+	}
+	return r
+}
+
+// Markdown 将结果渲染为Markdown表格。
+func (r *Result) Markdown() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "**%s**\n\n", r.Poll.Title)
+	for _, q := range r.Questions {
+		if q.Title != "" {
+			fmt.Fprintf(&sb, "%s\n\n", q.Title)
+		}
+		sb.WriteString("| 选项 | 票数 | 占比 |\n| :--- | ---: | ---: |\n")
+		for _, o := range q.Options {
+			fmt.Fprintf(&sb, "| %s | %d | %.1f%% |\n", escapeCell(o.Text), o.Count, o.Percent)
+		}
+		sb.WriteString("\n")
+	}
+	fmt.Fprintf(&sb, "共 %d 人参与", r.Voters)
+	return sb.String()
+}
+
+func escapeCell(s string) string {
+	return strings.NewReplacer("|", "\\|", "\n", " ").Replace(s)
+}
+
+// Card 生成结果卡片：在选项文案后附加票数，并禁用所有选项。
+// 附加票数后的卡片会重新校验，不满足平台限制时返回 wecomapi.ValidationErrors。
+func (r *Result) Card() (*wecomapi.TemplateCard, error) {
+	card, err := r.Poll.Card()
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]map[string]int, len(r.Questions))
+	for _, q := range r.Questions {
+		counts[q.Key] = make(map[string]int, len(q.Options))
+		for _, o := range q.Options {
+			counts[q.Key][o.ID] = o.Count
+		}
+	}
+	if cb := card.Checkbox; cb != nil {
+		cb.Disable = true
+		for i := range cb.OptionList {
+			o := &cb.OptionList[i]
+			o.Text = fmt.Sprintf("%s (%d)", o.Text, counts[cb.QuestionKey][o.ID])
+		}
+	}
+	for i := range card.SelectList {
+		s := &card.SelectList[i]
+		s.Disable = true
+		for j := range s.OptionList {
+			o := &s.OptionList[j]
+			o.Text = fmt.Sprintf("%s (%d)", o.Text, counts[s.QuestionKey][o.ID])
+		}
+	}
+	card.MainTitle.Desc = fmt.Sprintf("共 %d 人参与", r.Voters)
+	card.SubmitButton.Text = "已结束"
+	if err := card.Validate(); err != nil {
+		return nil, err
+	}
+	return card, nil
+}
+
+// WriteCSV 以CSV格式导出各选项的统计。
+func (r *Result) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"question_key", "question", "option_id", "option", "count", "percent"}); err != nil {
+		return err
+	}
+	for _, q := range r.Questions {
+		for _, o := range q.Options {
+			row := []string{q.Key, q.Title, o.ID, o.Text, strconv.Itoa(o.Count), strconv.FormatFloat(o.Percent, 'f', 1, 64)}
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteAnswersCSV 以CSV格式导出每个用户的作答明细。
+func (r *Result) WriteAnswersCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"user_id", "question_key", "option_ids", "answered_at"}); err != nil {
+		return err
+	}
+	for _, a := range r.Answers {
+		for _, q := range r.Poll.Questions {
+			ids, ok := a.Selections[q.Key]
+			if !ok {
+				continue
+			}
+			row := []string{a.UserID, q.Key, strings.Join(ids, ";"), a.AnsweredAt.Format(time.RFC3339)}
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON 以JSON格式导出完整结果。
+func (r *Result) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
@@ -0,0 +1,99 @@
+package wecompoll
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ErrPollNotFound 投票不存在。
+var ErrPollNotFound = errors.New("wecompoll: poll not found")
+
+// Answer 一个用户对投票的作答，同一用户再次提交时整体替换。
+type Answer struct {
+	UserID     string              `json:"user_id"`     // 作答用户
+	Selections map[string][]string `json:"selections"`  // 按问题key分组的选项ID
+	AnsweredAt time.Time           `json:"answered_at"` // 最近一次作答时间
+}
+
+// Store 投票持久化接口。
+type Store interface {
+	// SavePoll 保存投票，已存在时覆盖。
+	SavePoll(ctx context.Context, p *Poll) error
+	// LoadPoll 读取投票，不存在时返回 ErrPollNotFound。
+	LoadPoll(ctx context.Context, pollID string) (*Poll, error)
+	// SaveAnswer 保存用户作答，替换该用户之前的作答。
+	SaveAnswer(ctx context.Context, pollID string, a *Answer) error
+	// Answers 返回投票的全部作答。
+	Answers(ctx context.Context, pollID string) ([]*Answer, error)
+}
+
+// MemoryStore 基于内存的投票存储。
+type MemoryStore struct {
+	mu      sync.RWMutex
+	polls   map[string]*Poll
+	answers map[string]map[string]*Answer
+}
+
+// NewMemoryStore 创建内存投票存储。
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		polls:   make(map[string]*Poll),
+		answers: make(map[string]map[string]*Answer),
+	}
+}
+
+// SavePoll 保存投票。
+func (m *MemoryStore) SavePoll(_ context.Context, p *Poll) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.polls[p.ID] = p
+	return nil
+}
+
+// LoadPoll 读取投票。
+func (m *MemoryStore) LoadPoll(_ context.Context, pollID string) (*Poll, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	p, ok := m.polls[pollID]
+	if !ok {
+		return nil, ErrPollNotFound
+	}
+	return p, nil
+}
+
+// SaveAnswer 保存用户作答。
+func (m *MemoryStore) SaveAnswer(_ context.Context, pollID string, a *Answer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.polls[pollID]; !ok {
+		return ErrPollNotFound
+	}
+	byUser, ok := m.answers[pollID]
+	if !ok {
+		byUser = make(map[string]*Answer)
+		m.answers[pollID] = byUser
+	}
+	byUser[a.UserID] = a
+	return nil
+}
+
+// Answers 返回按作答时间排序的全部作答。
+func (m *MemoryStore) Answers(_ context.Context, pollID string) ([]*Answer, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if _, ok := m.polls[pollID]; !ok {
+		return nil, ErrPollNotFound
+	}
+	answers := make([]*Answer, 0, len(m.answers[pollID]))
+	for _, a := range m.answers[pollID] {
+		answers = append(answers, a)
+	}
+	slices.SortFunc(answers, func(a, b *Answer) int {
+		return cmp.Or(a.AnsweredAt.Compare(b.AnsweredAt), cmp.Compare(a.UserID, b.UserID))
+	})
+	return answers, nil
+}
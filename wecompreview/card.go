@@ -0,0 +1,124 @@
+// Package wecompreview 将模板卡片和Markdown回复渲染为近似企业微信客户端样式的静态HTML，
+// 便于在不发送真实消息的情况下预览。
+package wecompreview
+
+import (
+	_ "embed"
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/go-sphere/wecom-bot-api/wecomapi"
+)
+
+//go:embed preview.css
+var css string
+
+var funcs = template.FuncMap{
+	"markdown": RenderMarkdown,
+	"sourceColor": func(c wecomapi.SourceDescColor) string {
+		switch c {
+		case wecomapi.SourceDescColorBlack:
+			return "black"
+		case wecomapi.SourceDescColorRed:
+			return "red"
+		case wecomapi.SourceDescColorGreen:
+			return "green"
+		}
+		return "gray"
+	},
+	"buttonStyle": func(s wecomapi.ButtonStyle) int {
+		if s < wecomapi.ButtonStylePrimary || s > wecomapi.ButtonStyleGray {
+			return int(wecomapi.ButtonStylePrimary)
+		}
+		return int(s)
+	},
+	"selected": func(s wecomapi.SelectionItem) string {
+		for _, o := range s.OptionList {
+			if o.ID == s.SelectedID {
+				return o.Text
+			}
+		}
+		if len(s.OptionList) > 0 {
+			return s.OptionList[0].Text // 不填或错填时默认第一个
+		}
+		return ""
+	},
+	"lines": func(s string) []string {
+		return strings.Split(s, "\n")
+	},
+	"css": func() template.CSS {
+		return template.CSS(css)
+	},
+}
+
+var templates = template.Must(template.New("preview").Funcs(funcs).Parse(pageTemplate))
+
+// RenderCard 将模板卡片渲染为HTML片段。
+func RenderCard(w io.Writer, card *wecomapi.TemplateCard) error {
+	return templates.ExecuteTemplate(w, "card", card)
+}
+
+// RenderReply 将被动回复渲染为HTML片段，支持文本、Markdown、模板卡片和流式消息。
+func RenderReply(w io.Writer, reply *wecomapi.PassiveReply) error {
+	return templates.ExecuteTemplate(w, "reply", reply)
+}
+
+// Item 画廊中的一项预览，Card 和 Reply 二选一。
+type Item struct {
+	Name  string                 // 显示名称，通常为文件名
+	Card  *wecomapi.TemplateCard // 模板卡片
+	Reply *wecomapi.PassiveReply // 被动回复
+	Err   error                  // 加载或校验错误
+}
+
+// RenderPage 渲染包含样式的完整HTML画廊页面。
+func RenderPage(w io.Writer, title string, items []Item) error {
+	return templates.ExecuteTemplate(w, "page", struct {
+		Title string
+		Items []Item
+	}{title, items})
+}
+
+const pageTemplate = `
+{{define "page"}}<!DOCTYPE html>
+<html lang="zh-CN"><head><meta charset="utf-8"><title>{{.Title}}</title><style>{{css}}</style></head>
+<body><h1 class="gallery-title">{{.Title}}</h1><div class="gallery">
+{{range .Items}}<section class="item"><h2>{{.Name}}</h2>
+{{if .Err}}<pre class="error">{{.Err}}</pre>{{end}}
+{{if .Card}}{{template "card" .Card}}{{else if .Reply}}{{template "reply" .Reply}}{{end}}
+</section>{{end}}
+</div></body></html>
+{{end}}
+
+{{define "reply"}}<div class="bubble">
+{{if .Text}}<div class="text">{{range lines .Text.Content}}{{.}}<br>{{end}}</div>{{end}}
+{{if .Markdown}}<div class="markdown">{{markdown .Markdown.Content}}</div>{{end}}
+{{if .Stream}}<div class="markdown stream">{{markdown .Stream.Content}}
+{{range .Stream.MsgItem}}{{if .Image}}<div class="stream-image">[图片]</div>{{end}}{{end}}
+{{if not .Stream.Finish}}<span class="cursor">▍</span>{{end}}</div>{{end}}
+{{if .ResponseType}}<div class="meta">更新卡片{{with .UserIDs}}：{{range $i, $u := .}}{{if $i}}、{{end}}{{$u}}{{end}}{{end}}</div>{{end}}
+</div>
+{{if .TemplateCard}}{{template "card" .TemplateCard}}{{end}}
+{{end}}
+
+{{define "card"}}<div class="card card-{{.CardType}}">
+{{with .Source}}<div class="source">{{if .IconURL}}<img src="{{.IconURL}}" alt="">{{end}}<span class="{{sourceColor .DescColor}}">{{.Desc}}</span></div>{{end}}
+{{with .ActionMenu}}<div class="action-menu" title="{{.Desc}}">···<ul>{{range .ActionList}}<li>{{.Text}}</li>{{end}}</ul></div>{{end}}
+{{with .MainTitle}}<div class="main-title"><div class="title">{{.Title}}</div>{{if .Desc}}<div class="desc">{{.Desc}}</div>{{end}}</div>{{end}}
+{{with .EmphasisContent}}<div class="emphasis"><div class="title">{{.Title}}</div><div class="desc">{{.Desc}}</div></div>{{end}}
+{{with .QuoteArea}}<div class="quote">{{if .Title}}<div class="title">{{.Title}}</div>{{end}}<div class="text">{{range lines .QuoteText}}{{.}}<br>{{end}}</div></div>{{end}}
+{{with .ImageTextArea}}<div class="image-text"><img src="{{.ImageURL}}" alt=""><div><div class="title">{{.Title}}</div><div class="desc">{{.Desc}}</div></div></div>{{end}}
+{{with .CardImage}}<div class="card-image"><img src="{{.URL}}" alt="" style="aspect-ratio: {{if .AspectRatio}}{{.AspectRatio}}{{else}}1.3{{end}}"></div>{{end}}
+{{if .SubTitleText}}<div class="sub-title">{{range lines .SubTitleText}}{{.}}<br>{{end}}</div>{{end}}
+{{range .VerticalContentList}}<div class="vertical"><div class="title">{{.Title}}</div><div class="desc">{{.Desc}}</div></div>{{end}}
+{{if .HorizontalContentList}}<dl class="horizontal">{{range .HorizontalContentList}}<dt>{{.KeyName}}</dt><dd class="type-{{.Type}}">{{.Value}}</dd>{{end}}</dl>{{end}}
+{{with .ButtonSelection}}<div class="select{{if .Disable}} disabled{{end}}"><span class="label">{{.Title}}</span><span class="value">{{selected .}} ▾</span></div>{{end}}
+{{with .Checkbox}}<ul class="checkbox{{if .Disable}} disabled{{end}} mode-{{.Mode}}">{{range .OptionList}}<li class="{{if .IsChecked}}checked{{end}}"><span class="box"></span>{{.Text}}</li>{{end}}</ul>{{end}}
+{{range .SelectList}}<div class="select{{if .Disable}} disabled{{end}}"><span class="label">{{.Title}}</span><span class="value">{{selected .}} ▾</span></div>{{end}}
+{{if .JumpList}}<ul class="jump-list">{{range .JumpList}}<li class="jump-{{.Type}}">{{.Title}}<span class="arrow">›</span></li>{{end}}</ul>{{end}}
+{{if .ButtonList}}<div class="buttons">{{range .ButtonList}}<button class="style-{{buttonStyle .Style}}">{{.Text}}</button>{{end}}</div>{{end}}
+{{with .SubmitButton}}<div class="buttons"><button class="style-1 submit">{{.Text}}</button></div>{{end}}
+{{with .CardAction}}{{if .Type}}<div class="card-action">{{if .URL}}{{.URL}}{{else}}小程序 {{.AppID}}{{end}}</div>{{end}}{{end}}
+</div>{{end}}
+`
@@ -0,0 +1,148 @@
+package wecompreview
+
+import (
+	"html"
+	"html/template"
+	"regexp"
+	"strings"
+)
+
+var (
+	reImage      = regexp.MustCompile(`!\[([^\]]*)\]\((https?://[^)\s]+)\)`)
+	reLink       = regexp.MustCompile(`\[([^\]]+)\]\((https?://[^)\s]+)\)`)
+	reBold       = regexp.MustCompile(`\*\*(.+?)\*\*`)
+	reItalic     = regexp.MustCompile(`\*(.+?)\*`)
+	reCode       = regexp.MustCompile("`([^`]+)`")
+	reFont       = regexp.MustCompile(`&lt;font color=(?:&#34;|&#39;)(info|comment|warning)(?:&#34;|&#39;)&gt;(.*?)&lt;/font&gt;`)
+	reHeading    = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	reOrdered    = regexp.MustCompile(`^\s*(\d+)\.\s+(.*)$`)
+	reUnordered  = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	reTableSep   = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+	reHorizontal = regexp.MustCompile(`^\s*(-{3,}|\*{3,})\s*$`)
+)
+
+// RenderMarkdown 将企业微信支持的Markdown子集渲染为HTML：标题、加粗、斜体、
+// 列表、引用、链接、图片、行内代码、代码块、表格、分割线和 <font color> 颜色。
+func RenderMarkdown(content string) template.HTML {
+	r := &mdRenderer{}
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		switch {
+		case strings.HasPrefix(strings.TrimSpace(line), "```"):
+			r.closeList()
+			i = r.codeBlock(lines, i)
+		case isTableRow(line) && i+1 < len(lines) && reTableSep.MatchString(lines[i+1]):
+			r.closeList()
+			i = r.table(lines, i)
+		case reHorizontal.MatchString(line):
+			r.closeList()
+			r.sb.WriteString("<hr>")
+		case reHeading.MatchString(line):
+			r.closeList()
+			m := reHeading.FindStringSubmatch(line)
+			level := string(rune('0' + len(m[1])))
+			r.sb.WriteString("<h" + level + ">" + inline(m[2]) + "</h" + level + ">")
+		case strings.HasPrefix(line, ">"):
+			r.closeList()
+			depth := len(line) - len(strings.TrimLeft(line, ">"))
+			text := strings.TrimSpace(line[depth:])
+			r.sb.WriteString(strings.Repeat("<blockquote>", depth) + inline(text) + strings.Repeat("</blockquote>", depth))
+		case reUnordered.MatchString(line):
+			m := reUnordered.FindStringSubmatch(line)
+			r.listItem("ul", len(m[1])/2, m[2])
+		case reOrdered.MatchString(line):
+			m := reOrdered.FindStringSubmatch(line)
+			r.listItem("ol", 0, m[2])
+		case strings.TrimSpace(line) == "":
+			r.closeList()
+		default:
+			r.closeList()
+			r.sb.WriteString("<p>" + inline(line) + "</p>")
+		}
+	}
+	r.closeList()
+	return template.HTML(r.sb.String())
+}
+
+type mdRenderer struct {
+	sb    strings.Builder
+	lists []string // 当前打开的列表标签栈
+}
+
+func (r *mdRenderer) listItem(tag string, depth int, text string) {
+	for len(r.lists) > depth+1 || (len(r.lists) == depth+1 && r.lists[depth] != tag) {
+		r.sb.WriteString("</" + r.lists[len(r.lists)-1] + ">")
+		r.lists = r.lists[:len(r.lists)-1]
+	}
+	for len(r.lists) < depth+1 {
+		r.sb.WriteString("<" + tag + ">")
+		r.lists = append(r.lists, tag)
+	}
+	r.sb.WriteString("<li>" + inline(text) + "</li>")
+}
+
+func (r *mdRenderer) closeList() {
+	for i := len(r.lists) - 1; i >= 0; i-- {
+		r.sb.WriteString("</" + r.lists[i] + ">")
+	}
+	r.lists = r.lists[:0]
+}
+
+func (r *mdRenderer) codeBlock(lines []string, start int) int {
+	r.sb.WriteString("<pre><code>")
+	i := start + 1
+	for ; i < len(lines); i++ {
+		if strings.HasPrefix(strings.TrimSpace(lines[i]), "```") {
+			break
+		}
+		r.sb.WriteString(html.EscapeString(lines[i]) + "\n")
+	}
+	r.sb.WriteString("</code></pre>")
+	return i
+}
+
+func (r *mdRenderer) table(lines []string, start int) int {
+	r.sb.WriteString("<table><thead><tr>")
+	for _, cell := range splitRow(lines[start]) {
+		r.sb.WriteString("<th>" + inline(cell) + "</th>")
+	}
+	r.sb.WriteString("</tr></thead><tbody>")
+	i := start + 2
+	for ; i < len(lines) && isTableRow(lines[i]); i++ {
+		r.sb.WriteString("<tr>")
+		for _, cell := range splitRow(lines[i]) {
+			r.sb.WriteString("<td>" + inline(cell) + "</td>")
+		}
+		r.sb.WriteString("</tr>")
+	}
+	r.sb.WriteString("</tbody></table>")
+	return i - 1
+}
+
+func isTableRow(line string) bool {
+	return strings.Count(line, "|") >= 2
+}
+
+func splitRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	line = strings.TrimSuffix(line, "|")
+	cells := strings.Split(line, "|")
+	for i := range cells {
+		cells[i] = strings.TrimSpace(cells[i])
+	}
+	return cells
+}
+
+// inline 渲染行内元素，先转义HTML再替换Markdown标记。
+func inline(text string) string {
+	s := html.EscapeString(text)
+	s = reCode.ReplaceAllString(s, "<code>$1</code>")
+	s = reImage.ReplaceAllString(s, `<img src="$2" alt="$1">`)
+	s = reLink.ReplaceAllString(s, `<a href="$2">$1</a>`)
+	s = reBold.ReplaceAllString(s, "<strong>$1</strong>")
+	s = reItalic.ReplaceAllString(s, "<em>$1</em>")
+	s = reFont.ReplaceAllString(s, `<span class="font-$1">$2</span>`)
+	return s
+}
@@ -0,0 +1,102 @@
+package wecompreview
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-sphere/wecom-bot-api/wecomapi"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata")
+
+// golden 比较输出与testdata中的golden文件，-update时重写文件。
+func golden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	file := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(file, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("%v (run with -update to create it)", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s mismatch\n got: %s\nwant: %s", file, got, want)
+	}
+}
+
+func TestRenderGolden(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatal("no testdata inputs")
+	}
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".json")
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var buf bytes.Buffer
+			if strings.HasPrefix(name, "reply_") {
+				var reply wecomapi.PassiveReply
+				if err := json.Unmarshal(data, &reply); err != nil {
+					t.Fatal(err)
+				}
+				err = RenderReply(&buf, &reply)
+			} else {
+				var card wecomapi.TemplateCard
+				if err := json.Unmarshal(data, &card); err != nil {
+					t.Fatal(err)
+				}
+				err = RenderCard(&buf, &card)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			golden(t, name+".html", buf.Bytes())
+		})
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"escape html", `<script>alert(1)</script> & "q"`, `<p>&lt;script&gt;alert(1)&lt;/script&gt; &amp; &#34;q&#34;</p>`},
+		{"escape in code block", "```\n<b>x</b>\n```", "<pre><code>&lt;b&gt;x&lt;/b&gt;\n</code></pre>"},
+		{"escape in inline code", "`<i>`", `<p><code>&lt;i&gt;</code></p>`},
+		{"http link", "[a](http://example.com/x)", `<p><a href="http://example.com/x">a</a></p>`},
+		{"https link", "[a](https://example.com)", `<p><a href="https://example.com">a</a></p>`},
+		{"javascript link", "[a](javascript:alert(1))", `<p>[a](javascript:alert(1))</p>`},
+		{"data link", "[a](data:text/html,x)", `<p>[a](data:text/html,x)</p>`},
+		{"quoted link url", `[a](https://example.com/"onmouseover="x)`, `<p><a href="https://example.com/&#34;onmouseover=&#34;x">a</a></p>`},
+		{"https image", "![i](https://example.com/a.png)", `<p><img src="https://example.com/a.png" alt="i"></p>`},
+		{"javascript image", "![i](javascript:x)", `<p>![i](javascript:x)</p>`},
+		{"font color", `<font color="info">ok</font>`, `<p><span class="font-info">ok</span></p>`},
+		{"unknown font color", `<font color="red">x</font>`, `<p>&lt;font color=&#34;red&#34;&gt;x&lt;/font&gt;</p>`},
+		{"heading", "## 标题 **粗**", `<h2>标题 <strong>粗</strong></h2>`},
+		{"nested list", "- a\n  - b\n- c", `<ul><li>a</li><ul><li>b</li></ul><li>c</li></ul>`},
+		{"table", "| a | b |\n| :- | -: |\n| 1 | 2 |", `<table><thead><tr><th>a</th><th>b</th></tr></thead><tbody><tr><td>1</td><td>2</td></tr></tbody></table>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(RenderMarkdown(tt.in)); got != tt.want {
+				t.Errorf("RenderMarkdown(%q)\n got: %s\nwant: %s", tt.in, got, tt.want)
+			}
+		})
+	}
+}
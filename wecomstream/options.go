@@ -0,0 +1,89 @@
+package wecomstream
+
+import (
+	"time"
+
+	"github.com/go-sphere/wecom-bot-api/wecomapi"
+)
+
+// DefaultTimeout 流式输出的默认超时时间，企业微信从用户发消息开始最多推送6分钟的流式消息刷新。
+const DefaultTimeout = 6 * time.Minute
+
+type config struct {
+	mapError    ErrorMapper
+	onStats     func(Stats)
+	streamOpts  []wecomapi.StreamOption
+	closeOpts   []wecomapi.StreamOption
+	inlineThink bool
+	timeout     time.Duration
+	client      *wecomapi.ActiveReplyClient
+	onError     func(id string, err error)
+}
+
+// Option 配置流式输出。
+type Option func(*config)
+
+func newConfig(opts []Option) *config {
+	cfg := &config{mapError: DefaultErrorMapper, timeout: DefaultTimeout}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// WithErrorMapper 设置错误到用户可见信息的映射，默认为 DefaultErrorMapper。
+func WithErrorMapper(fn ErrorMapper) Option {
+	return func(c *config) {
+		c.mapError = fn
+	}
+}
+
+// WithStatsHandler 设置输出结束时的统计回调，可用于上报token用量和延迟。
+func WithStatsHandler(fn func(Stats)) Option {
+	return func(c *config) {
+		c.onStats = fn
+	}
+}
+
+// WithStreamOptions 设置创建 StreamWriter 时的选项，如超限策略和思考过程折叠。
+func WithStreamOptions(opts ...wecomapi.StreamOption) Option {
+	return func(c *config) {
+		c.streamOpts = append(c.streamOpts, opts...)
+	}
+}
+
+// WithCloseOptions 设置结束流式消息时的选项，如附带的图片。
+func WithCloseOptions(opts ...wecomapi.StreamOption) Option {
+	return func(c *config) {
+		c.closeOpts = append(c.closeOpts, opts...)
+	}
+}
+
+// WithInlineThink 将回答内容中内联的 <think></think> 标签拆分为思考过程。
+func WithInlineThink() Option {
+	return func(c *config) {
+		c.inlineThink = true
+	}
+}
+
+// WithTimeout 设置 Sessions 中流式输出的超时时间，默认为 DefaultTimeout。
+func WithTimeout(d time.Duration) Option {
+	return func(c *config) {
+		c.timeout = d
+	}
+}
+
+// WithActiveReplyClient 设置 Sessions 发送超限剩余内容所用的主动回复客户端。
+// 调试时可传入启用 wecomapi.WithActiveReplyCheck 的客户端检查发送的回复。
+func WithActiveReplyClient(client *wecomapi.ActiveReplyClient) Option {
+	return func(c *config) {
+		c.client = client
+	}
+}
+
+// WithErrorHandler 设置结束选项无效或 Sessions 发送超限剩余内容失败时的回调。
+func WithErrorHandler(fn func(id string, err error)) Option {
+	return func(c *config) {
+		c.onError = fn
+	}
+}
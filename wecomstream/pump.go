@@ -0,0 +1,143 @@
+package wecomstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-sphere/wecom-bot-api/wecomapi"
+)
+
+// 默认展示给用户的错误信息。
+const (
+	MessageTimeout  = "回答超时，请稍后重试。"
+	MessageCanceled = "回答已取消。"
+	MessageFailed   = "回答生成失败，请稍后重试。"
+	MessageLimited  = "请求过于频繁，请稍后重试。"
+)
+
+// ErrorMapper 将输出过程中的错误映射为展示给用户的信息。
+type ErrorMapper func(err error) string
+
+// DefaultErrorMapper 默认的错误映射，不向用户暴露内部错误细节。
+func DefaultErrorMapper(err error) string {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return MessageTimeout
+	case errors.Is(err, context.Canceled):
+		return MessageCanceled
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusTooManyRequests {
+		return MessageLimited
+	}
+	return MessageFailed
+}
+
+// Stats 一次流式输出的统计信息。
+type Stats struct {
+	StreamID       string
+	Chunks         int                  // 收到的片段数
+	ContentBytes   int                  // 回答内容字节数
+	ReasoningBytes int                  // 思考过程字节数
+	FirstChunk     time.Duration        // 首个片段的延迟
+	Duration       time.Duration        // 总耗时
+	Usage          *Usage               // 来源上报的token用量
+	Stream         wecomapi.StreamStats // 流式消息的字节统计
+	Err            error                // 输出过程中的错误，正常结束时为nil
+}
+
+// UserError 展示给用户的错误，Unwrap 返回原始错误。
+type UserError struct {
+	Message string
+	Err     error
+}
+
+func (e *UserError) Error() string { return e.Message }
+
+func (e *UserError) Unwrap() error { return e.Err }
+
+// Pump 将 src 的输出写入 w，结束后关闭 w：正常结束时调用 Close，
+// 出错或 ctx 结束时以映射后的错误信息调用 CloseWithError。
+func Pump(ctx context.Context, w *wecomapi.StreamWriter, src Source, opts ...Option) Stats {
+	cfg := newConfig(opts)
+	return pump(ctx, w, src, cfg)
+}
+
+func pump(ctx context.Context, w *wecomapi.StreamWriter, src Source, cfg *config) Stats {
+	start := time.Now()
+	stats := Stats{StreamID: w.ID()}
+	var tagged *wecomapi.ThinkTagWriter
+	if cfg.inlineThink {
+		tagged = w.TaggedWriter()
+	}
+	err := safeSource(ctx, src, func(c Chunk) error {
+		if stats.Chunks == 0 {
+			stats.FirstChunk = time.Since(start)
+		}
+		stats.Chunks++
+		stats.ContentBytes += len(c.Content)
+		stats.ReasoningBytes += len(c.Reasoning)
+		if c.Usage != nil {
+			stats.Usage = c.Usage
+		}
+		if c.Reasoning != "" {
+			if _, err := w.WriteReasoning(c.Reasoning); err != nil {
+				return err
+			}
+		}
+		if c.Content == "" {
+			return nil
+		}
+		var err error
+		if tagged != nil {
+			_, err = tagged.WriteString(c.Content)
+		} else {
+			_, err = w.WriteString(c.Content)
+		}
+		return err
+	})
+	if tagged != nil {
+		if ferr := tagged.Flush(); err == nil {
+			err = ferr
+		}
+	}
+	stats.Err = err
+	if cerr := closeStream(w, err, cfg); cerr != nil && cfg.onError != nil {
+		cfg.onError(w.ID(), cerr)
+	}
+	stats.Duration = time.Since(start)
+	stats.Stream = w.Stats()
+	if cfg.onStats != nil {
+		cfg.onStats(stats)
+	}
+	return stats
+}
+
+// closeStream 结束流式消息。结束选项无效（如图片过多、反馈ID设置过晚）时
+// 不带选项结束，保证流式消息不会停留在未结束状态，并返回选项错误。
+func closeStream(w *wecomapi.StreamWriter, err error, cfg *config) error {
+	finish := func(opts ...wecomapi.StreamOption) error {
+		if err != nil {
+			return w.CloseWithError(&UserError{Message: cfg.mapError(err), Err: err}, opts...)
+		}
+		return w.Close(opts...)
+	}
+	cerr := finish(cfg.closeOpts...)
+	if cerr == nil || errors.Is(cerr, wecomapi.ErrStreamClosed) {
+		return nil
+	}
+	_ = finish()
+	return cerr
+}
+
+func safeSource(ctx context.Context, src Source, yield func(Chunk) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("wecomstream: source panicked: %v", r)
+		}
+	}()
+	return src(ctx, yield)
+}
@@ -0,0 +1,118 @@
+package wecomstream
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/go-sphere/wecom-bot-api/wecomapi"
+)
+
+// ErrSessionNotFound 流式消息会话不存在或已结束。
+var ErrSessionNotFound = errors.New("wecomstream: session not found")
+
+type session struct {
+	w         *wecomapi.StreamWriter
+	cancel    context.CancelFunc
+	cb        *wecomapi.Callback // 触发流式消息的回调，用于发送超限的剩余内容
+	createdAt time.Time
+}
+
+// Sessions 管理进行中的流式消息：在后台将 Source 写入 StreamWriter，
+// 并以最新内容回复流式消息刷新回调。
+type Sessions struct {
+	mu       sync.Mutex
+	sessions map[string]*session
+	opts     []Option
+	now      func() time.Time
+}
+
+// NewSessions 创建流式消息会话管理器，opts 作为每个会话的默认选项。
+func NewSessions(opts ...Option) *Sessions {
+	return &Sessions{
+		sessions: make(map[string]*session),
+		opts:     opts,
+		now:      time.Now,
+	}
+}
+
+// Start 为回调创建流式消息会话，在后台输出 src，并返回首次流式消息回复。
+// 使用 wecomapi.OverflowContinue 策略时，超限的剩余内容会在输出结束后通过回调的 response_url 发送。
+func (s *Sessions) Start(ctx context.Context, cb *wecomapi.Callback, src Source, opts ...Option) (*wecomapi.PassiveReply, error) {
+	cfg := newConfig(append(append([]Option(nil), s.opts...), opts...))
+	w := wecomapi.NewStreamWriter("", cfg.streamOpts...)
+	runCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cfg.timeout)
+	now := s.now()
+	sess := &session{w: w, cancel: cancel, cb: cb, createdAt: now}
+
+	s.mu.Lock()
+	s.sweep(now, cfg.timeout)
+	s.sessions[w.ID()] = sess
+	s.mu.Unlock()
+
+	go func() {
+		defer cancel()
+		pump(runCtx, w, src, cfg)
+		if w.Overflowed() && w.OverflowContent() != "" && sess.cb.ResponseURL != "" {
+			client := cfg.client
+			if client == nil {
+				client = wecomapi.NewActiveReplyClient(nil)
+			}
+			if err := w.SendOverflow(context.WithoutCancel(ctx), client, sess.cb); err != nil && cfg.onError != nil {
+				cfg.onError(w.ID(), err)
+			}
+		}
+	}()
+	return w.Reply(), nil
+}
+
+// HandleStream 以最新内容回复流式消息刷新回调，流式消息结束后移除会话，可直接注册到 wecomapi.Router。
+func (s *Sessions) HandleStream(ctx context.Context, cb *wecomapi.Callback) (*wecomapi.PassiveReply, error) {
+	if cb.Stream == nil {
+		return nil, errors.New("wecomstream: callback is not a stream refresh")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, ok := s.sessions[cb.Stream.ID]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	reply := sess.w.Reply()
+	if reply.Stream.Finish {
+		delete(s.sessions, cb.Stream.ID)
+	}
+	return reply, nil
+}
+
+// Writer 返回会话的 StreamWriter。
+func (s *Sessions) Writer(id string) (*wecomapi.StreamWriter, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, ok := s.sessions[id]
+	if !ok {
+		return nil, false
+	}
+	return sess.w, true
+}
+
+// Cancel 取消会话的输出，流式消息以取消信息结束。
+func (s *Sessions) Cancel(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, ok := s.sessions[id]
+	if ok {
+		sess.cancel()
+	}
+	return ok
+}
+
+// sweep 清理超过最长推送时间的会话，调用方需持有锁。
+func (s *Sessions) sweep(now time.Time, timeout time.Duration) {
+	for id, sess := range s.sessions {
+		if now.Sub(sess.createdAt) > max(timeout, DefaultTimeout) {
+			sess.cancel()
+			delete(s.sessions, id)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package wecomstream
+
+import (
+	"context"
+	"iter"
+)
+
+// Usage 模型调用的token用量。
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// Chunk 模型输出的一个增量片段。
+type Chunk struct {
+	Content   string // 回答内容
+	Reasoning string // 思考过程
+	Usage     *Usage // token用量，通常仅在最后一个片段中出现
+}
+
+// Source 增量片段的来源，依次以片段调用yield，直到输出结束、出错或ctx结束。
+// yield 返回错误时应立即停止并返回该错误。
+type Source func(ctx context.Context, yield func(Chunk) error) error
+
+// FromSeq 将 iter.Seq[string] 适配为 Source，每个元素作为回答内容。
+func FromSeq(seq iter.Seq[string]) Source {
+	return func(ctx context.Context, yield func(Chunk) error) error {
+		for s := range seq {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := yield(Chunk{Content: s}); err != nil {
+				return err
+			}
+		}
+		return ctx.Err()
+	}
+}
+
+// FromChunkSeq 将 iter.Seq2[Chunk, error] 适配为 Source，遇到错误时停止。
+func FromChunkSeq(seq iter.Seq2[Chunk, error]) Source {
+	return func(ctx context.Context, yield func(Chunk) error) error {
+		for c, err := range seq {
+			if err != nil {
+				return err
+			}
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			if err := yield(c); err != nil {
+				return err
+			}
+		}
+		return ctx.Err()
+	}
+}
+
+// FromChan 将 <-chan string 适配为 Source，通道关闭表示输出结束。
+func FromChan(ch <-chan string) Source {
+	return func(ctx context.Context, yield func(Chunk) error) error {
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case s, ok := <-ch:
+				if !ok {
+					return nil
+				}
+				if err := yield(Chunk{Content: s}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
+// FromChunkChan 将 <-chan Chunk 适配为 Source，errc 可为nil，
+// 通道关闭后若 errc 中有错误则返回该错误。
+func FromChunkChan(ch <-chan Chunk, errc <-chan error) Source {
+	return func(ctx context.Context, yield func(Chunk) error) error {
+		for {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case c, ok := <-ch:
+				if !ok {
+					if errc == nil {
+						return nil
+					}
+					select {
+					case err := <-errc:
+						return err
+					default:
+						return nil
+					}
+				}
+				if err := yield(c); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package wecomstream
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxSSELine SSE单行的最大字节数。
+const maxSSELine = 1 << 20
+
+// APIError 推理服务返回的错误。
+type APIError struct {
+	StatusCode int    // HTTP状态码，流内错误时为0
+	Message    string // 错误信息
+}
+
+func (e *APIError) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("wecomstream: api error: status %d: %s", e.StatusCode, e.Message)
+	}
+	return "wecomstream: api error: " + e.Message
+}
+
+// openAIChunk OpenAI兼容的 chat.completion.chunk 数据。
+type openAIChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content          string `json:"content"`
+			ReasoningContent string `json:"reasoning_content"`
+			Reasoning        string `json:"reasoning"`
+		} `json:"delta"`
+	} `json:"choices"`
+	Usage *Usage `json:"usage"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// FromSSE 将OpenAI兼容的Server-Sent Events流适配为 Source。
+// 解析 choices[0].delta 中的 content 和 reasoning_content（或 reasoning），
+// 以及 usage 字段，遇到 "data: [DONE]" 或流结束时停止。
+func FromSSE(r io.Reader) Source {
+	return func(ctx context.Context, yield func(Chunk) error) error {
+		sc := bufio.NewScanner(r)
+		sc.Buffer(make([]byte, 0, 64*1024), maxSSELine)
+		var data []string
+		for sc.Scan() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			line := sc.Text()
+			if line != "" {
+				if v, ok := sseField(line, "data"); ok {
+					data = append(data, v)
+				}
+				continue
+			}
+			if len(data) == 0 {
+				continue
+			}
+			payload := strings.Join(data, "\n")
+			data = data[:0]
+			if payload == "[DONE]" {
+				return nil
+			}
+			if err := yieldOpenAIChunk(payload, yield); err != nil {
+				return err
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := sc.Err(); err != nil {
+			return fmt.Errorf("wecomstream: read sse: %w", err)
+		}
+		if len(data) > 0 && strings.Join(data, "\n") != "[DONE]" {
+			return yieldOpenAIChunk(strings.Join(data, "\n"), yield)
+		}
+		return nil
+	}
+}
+
+// sseField 解析 "name: value" 形式的SSE字段，以冒号开头的注释行返回false。
+func sseField(line, name string) (string, bool) {
+	field, value, _ := strings.Cut(line, ":")
+	if field != name {
+		return "", false
+	}
+	return strings.TrimPrefix(value, " "), true
+}
+
+func yieldOpenAIChunk(payload string, yield func(Chunk) error) error {
+	var c openAIChunk
+	if err := json.Unmarshal([]byte(payload), &c); err != nil {
+		return fmt.Errorf("wecomstream: decode sse chunk: %w", err)
+	}
+	if c.Error != nil {
+		return &APIError{Message: c.Error.Message}
+	}
+	chunk := Chunk{Usage: c.Usage}
+	if len(c.Choices) > 0 {
+		d := c.Choices[0].Delta
+		chunk.Content = d.Content
+		chunk.Reasoning = d.ReasoningContent + d.Reasoning
+	}
+	if chunk == (Chunk{}) {
+		return nil
+	}
+	return yield(chunk)
+}
+
+// FromResponse 将流式HTTP响应适配为 Source。非200响应返回 *APIError，
+// ctx 结束时关闭响应体以中断读取，Source 返回时关闭响应体。
+func FromResponse(resp *http.Response) Source {
+	return func(ctx context.Context, yield func(Chunk) error) error {
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+			return &APIError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(body))}
+		}
+		stop := context.AfterFunc(ctx, func() { resp.Body.Close() })
+		defer stop()
+		return FromSSE(resp.Body)(ctx, yield)
+	}
+}
@@ -0,0 +1,184 @@
+package wecomstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func collect(ctx context.Context, src Source) ([]Chunk, error) {
+	var chunks []Chunk
+	err := src(ctx, func(c Chunk) error {
+		chunks = append(chunks, c)
+		return nil
+	})
+	return chunks, err
+}
+
+func delta(content string) string {
+	return fmt.Sprintf(`{"choices":[{"delta":{"content":%q}}]}`, content)
+}
+
+func TestFromSSE(t *testing.T) {
+	tests := []struct {
+		name    string
+		stream  string
+		want    []Chunk
+		wantErr string
+	}{
+		{
+			name:   "content and done",
+			stream: "data: " + delta("Hel") + "\n\ndata: " + delta("lo") + "\n\ndata: [DONE]\n\ndata: " + delta("ignored") + "\n\n",
+			want:   []Chunk{{Content: "Hel"}, {Content: "lo"}},
+		},
+		{
+			name:   "multi-line data",
+			stream: "data: {\"choices\":[{\"delta\":\ndata: {\"content\":\"a\"}}]}\n\n",
+			want:   []Chunk{{Content: "a"}},
+		},
+		{
+			name: "comments and other fields",
+			stream: ": keep-alive\n\nevent: message\nid: 1\nretry: 10\ndata:" + delta("x") + "\n\n" +
+				"data: {\"choices\":[]}\n\n",
+			want: []Chunk{{Content: "x"}},
+		},
+		{
+			name: "reasoning and usage",
+			stream: `data: {"choices":[{"delta":{"reasoning_content":"think"}}]}` + "\n\n" +
+				`data: {"choices":[{"delta":{"reasoning":"more"}}]}` + "\n\n" +
+				`data: {"choices":[],"usage":{"prompt_tokens":1,"completion_tokens":2,"total_tokens":3}}` + "\n\n",
+			want: []Chunk{
+				{Reasoning: "think"},
+				{Reasoning: "more"},
+				{Usage: &Usage{PromptTokens: 1, CompletionTokens: 2, TotalTokens: 3}},
+			},
+		},
+		{
+			name:   "trailing event without blank line",
+			stream: "data: " + delta("end"),
+			want:   []Chunk{{Content: "end"}},
+		},
+		{
+			name:    "in-stream error",
+			stream:  "data: " + delta("partial") + "\n\ndata: {\"error\":{\"message\":\"overloaded\"}}\n\n",
+			want:    []Chunk{{Content: "partial"}},
+			wantErr: "overloaded",
+		},
+		{
+			name:    "invalid json",
+			stream:  "data: {oops\n\n",
+			wantErr: "decode sse chunk",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := collect(context.Background(), FromSSE(strings.NewReader(tt.stream)))
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("error = %v, want %q", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("chunks = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromSSEInStreamAPIError(t *testing.T) {
+	_, err := collect(context.Background(), FromSSE(strings.NewReader(`data: {"error":{"message":"bad"}}`+"\n\n")))
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 0 || apiErr.Message != "bad" {
+		t.Fatalf("error = %#v, want in-stream APIError", err)
+	}
+}
+
+func TestFromResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, s := range []string{"a", "b"} {
+			fmt.Fprintf(w, "data: %s\n\n", delta(s))
+			w.(http.Flusher).Flush()
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := collect(context.Background(), FromResponse(resp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []Chunk{{Content: "a"}, {Content: "b"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("chunks = %+v, want %+v", got, want)
+	}
+}
+
+func TestFromResponseStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "rate limited", http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = collect(context.Background(), FromResponse(resp))
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("error = %v, want *APIError", err)
+	}
+	if apiErr.StatusCode != http.StatusTooManyRequests || apiErr.Message != "rate limited" {
+		t.Errorf("APIError = %+v", apiErr)
+	}
+}
+
+func TestFromResponseCancelClosesBody(t *testing.T) {
+	disconnected := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "data: %s\n\n", delta("first"))
+		w.(http.Flusher).Flush()
+		// 保持连接不结束，直到客户端关闭响应体。
+		<-r.Context().Done()
+		close(disconnected)
+	}))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- FromResponse(resp)(ctx, func(c Chunk) error {
+			cancel()
+			return nil
+		})
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("error = %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("source did not return after cancellation")
+	}
+	select {
+	case <-disconnected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("response body was not closed")
+	}
+}
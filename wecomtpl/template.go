@@ -0,0 +1,335 @@
+// Package wecomtpl 从JSON文件加载模板卡片定义，并使用 text/template 渲染其中的占位符。
+//
+// 模板文件的结构与 wecomapi.TemplateCard 的JSON结构一致，字符串字段中可以使用
+// {{.Name}} 等占位符，渲染时以请求数据替换。占位符只能出现在字符串值中。
+//
+// 默认只支持 .json 文件。本包不依赖第三方库，需要YAML时注册外部解码器：
+//
+//	set := wecomtpl.NewSet(
+//		wecomtpl.WithDecoder(".yaml", yaml.Unmarshal),
+//		wecomtpl.WithDecoder(".yml", yaml.Unmarshal),
+//	)
+package wecomtpl
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/go-sphere/wecom-bot-api/wecomapi"
+)
+
+// Decoder 将文件内容解码为由 map[string]any、[]any 和标量组成的通用结构。
+type Decoder func(data []byte, v any) error
+
+// Error 模板加载或渲染错误，包含出错的文件和字段路径。
+type Error struct {
+	File  string // 模板文件路径
+	Field string // 字段路径，如 main_title.title，整体错误时为空
+	Err   error
+}
+
+func (e *Error) Error() string {
+	if e.Field == "" {
+		return fmt.Sprintf("wecomtpl: %s: %v", e.File, e.Err)
+	}
+	return fmt.Sprintf("wecomtpl: %s: %s: %v", e.File, e.Field, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// ErrNotFound 模板不存在。
+var ErrNotFound = errors.New("wecomtpl: template not found")
+
+// Set 一组已加载的卡片模板。
+type Set struct {
+	decoders  map[string]Decoder
+	funcs     template.FuncMap
+	strict    bool
+	templates map[string]*cardTemplate
+}
+
+// Option 配置 Set。
+type Option func(*Set)
+
+// WithDecoder 为文件扩展名注册解码器，例如使用YAML库支持 .yaml 文件：
+//
+//	wecomtpl.WithDecoder(".yaml", yaml.Unmarshal)
+//
+// 解码器产生的对象必须是 map[string]any（如 gopkg.in/yaml.v3）。
+func WithDecoder(ext string, dec Decoder) Option {
+	return func(s *Set) {
+		s.decoders[ext] = dec
+	}
+}
+
+// WithFuncs 添加模板函数。
+func WithFuncs(funcs template.FuncMap) Option {
+	return func(s *Set) {
+		for name, fn := range funcs {
+			s.funcs[name] = fn
+		}
+	}
+}
+
+// WithStrict 渲染后使用 TemplateCard.ValidateStrict 校验，额外检查建议字数。
+func WithStrict() Option {
+	return func(s *Set) {
+		s.strict = true
+	}
+}
+
+// NewSet 创建模板集合，默认支持 .json 文件。
+func NewSet(opts ...Option) *Set {
+	s := &Set{
+		decoders:  map[string]Decoder{".json": decodeJSON},
+		funcs:     template.FuncMap{},
+		templates: make(map[string]*cardTemplate),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func decodeJSON(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return dec.Decode(v)
+}
+
+// Load 从文件系统加载匹配 fs.Glob 模式的模板文件，模板名为去掉扩展名的文件路径。
+// 扩展名没有注册解码器的文件将被忽略。
+func (s *Set) Load(fsys fs.FS, patterns ...string) error {
+	for _, pattern := range patterns {
+		files, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return err
+		}
+		for _, file := range files {
+			dec, ok := s.decoders[path.Ext(file)]
+			if !ok {
+				continue
+			}
+			data, err := fs.ReadFile(fsys, file)
+			if err != nil {
+				return &Error{File: file, Err: err}
+			}
+			t, err := s.parse(file, data, dec)
+			if err != nil {
+				return err
+			}
+			s.templates[strings.TrimSuffix(file, path.Ext(file))] = t
+		}
+	}
+	return nil
+}
+
+// Names 返回已加载的模板名。
+func (s *Set) Names() []string {
+	names := make([]string, 0, len(s.templates))
+	for name := range s.templates {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Render 以data渲染模板并校验结果。校验失败时 Error.Field 为第一个出错的字段，
+// Error.Err 为完整的 wecomapi.ValidationErrors。
+func (s *Set) Render(name string, data any) (*wecomapi.TemplateCard, error) {
+	t, ok := s.templates[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	tree, err := t.render("", t.tree, data)
+	if err != nil {
+		return nil, err
+	}
+	card, err := t.decode(tree)
+	if err != nil {
+		return nil, err
+	}
+	validate := card.Validate
+	if s.strict {
+		validate = card.ValidateStrict
+	}
+	if err := validate(); err != nil {
+		e := &Error{File: t.file, Err: err}
+		var errs wecomapi.ValidationErrors
+		var fe *wecomapi.FieldError
+		switch {
+		case errors.As(err, &errs) && len(errs) > 0:
+			e.Field = errs[0].Field
+		case errors.As(err, &fe):
+			e.Field = fe.Field
+		}
+		return nil, e
+	}
+	return card, nil
+}
+
+// cardTemplate 单个模板文件。
+type cardTemplate struct {
+	file      string
+	tree      any
+	templates map[string]*template.Template // 按字段路径索引的字符串模板
+}
+
+func (s *Set) parse(file string, data []byte, dec Decoder) (*cardTemplate, error) {
+	var tree any
+	if err := dec(data, &tree); err != nil {
+		return nil, &Error{File: file, Err: err}
+	}
+	t := &cardTemplate{file: file, tree: tree, templates: make(map[string]*template.Template)}
+	if err := s.compile(t, "", tree); err != nil {
+		return nil, err
+	}
+	// 占位符尚未替换时检查结构，尽早发现未知字段和类型错误。
+	if err := checkFields(reflect.TypeFor[wecomapi.TemplateCard](), "", tree); err != nil {
+		return nil, &Error{File: file, Field: err.Field, Err: errors.New(err.Reason)}
+	}
+	if _, err := t.decode(tree); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (s *Set) compile(t *cardTemplate, field string, node any) error {
+	switch v := node.(type) {
+	case map[string]any:
+		for key, child := range v {
+			if err := s.compile(t, joinField(field, key), child); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for i, child := range v {
+			if err := s.compile(t, field+"["+strconv.Itoa(i)+"]", child); err != nil {
+				return err
+			}
+		}
+	case string:
+		if !strings.Contains(v, "{{") {
+			return nil
+		}
+		tmpl, err := template.New(field).Funcs(s.funcs).Option("missingkey=error").Parse(v)
+		if err != nil {
+			return &Error{File: t.file, Field: field, Err: err}
+		}
+		t.templates[field] = tmpl
+	}
+	return nil
+}
+
+func (t *cardTemplate) render(field string, node, data any) (any, error) {
+	switch v := node.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, child := range v {
+			r, err := t.render(joinField(field, key), child, data)
+			if err != nil {
+				return nil, err
+			}
+			out[key] = r
+		}
+		return out, nil
+	case []any:
+		out := make([]any, len(v))
+		for i, child := range v {
+			r, err := t.render(field+"["+strconv.Itoa(i)+"]", child, data)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = r
+		}
+		return out, nil
+	case string:
+		tmpl, ok := t.templates[field]
+		if !ok {
+			return v, nil
+		}
+		var sb strings.Builder
+		if err := tmpl.Execute(&sb, data); err != nil {
+			return nil, &Error{File: t.file, Field: field, Err: err}
+		}
+		return sb.String(), nil
+	}
+	return node, nil
+}
+
+// decode 将通用结构转换为卡片，字段不存在或类型不符时报告字段路径。
+func (t *cardTemplate) decode(tree any) (*wecomapi.TemplateCard, error) {
+	data, err := json.Marshal(tree)
+	if err != nil {
+		return nil, &Error{File: t.file, Err: err}
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var card wecomapi.TemplateCard
+	if err := dec.Decode(&card); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return nil, &Error{File: t.file, Field: typeErr.Field, Err: err}
+		}
+		return nil, &Error{File: t.file, Err: err}
+	}
+	return &card, nil
+}
+
+// checkFields 按结构体的json标签检查未知字段。
+func checkFields(t reflect.Type, field string, node any) *wecomapi.FieldError {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch v := node.(type) {
+	case map[string]any:
+		if t.Kind() != reflect.Struct {
+			return nil
+		}
+		fields := make(map[string]reflect.Type, t.NumField())
+		for i := range t.NumField() {
+			f := t.Field(i)
+			name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if f.IsExported() && name != "" && name != "-" {
+				fields[name] = f.Type
+			}
+		}
+		for key, child := range v {
+			ft, ok := fields[key]
+			if !ok {
+				return &wecomapi.FieldError{Field: joinField(field, key), Reason: "unknown field"}
+			}
+			if err := checkFields(ft, joinField(field, key), child); err != nil {
+				return err
+			}
+		}
+	case []any:
+		if t.Kind() != reflect.Slice {
+			return nil
+		}
+		for i, child := range v {
+			if err := checkFields(t.Elem(), field+"["+strconv.Itoa(i)+"]", child); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func joinField(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	return prefix + "." + key
+}
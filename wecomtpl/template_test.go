@@ -0,0 +1,184 @@
+package wecomtpl
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"text/template"
+
+	"github.com/go-sphere/wecom-bot-api/wecomapi"
+)
+
+const approvalTemplate = `{
+	"card_type": "button_interaction",
+	"main_title": {"title": "{{.Title}}", "desc": "{{upper .Dept}}"},
+	"horizontal_content_list": [
+		{"keyname": "金额", "value": "{{printf \"%.2f\" .Amount}}"}
+	],
+	"button_list": [
+		{"text": "同意", "key": "approve", "style": 1},
+		{"text": "拒绝", "key": "reject", "style": 3}
+	],
+	"task_id": "{{.ID}}"
+}`
+
+type approvalData struct {
+	Title  string
+	Dept   string
+	Amount float64
+	ID     string
+}
+
+func loadSet(t *testing.T, files map[string]string, opts ...Option) (*Set, error) {
+	t.Helper()
+	fsys := fstest.MapFS{}
+	for name, data := range files {
+		fsys[name] = &fstest.MapFile{Data: []byte(data)}
+	}
+	s := NewSet(append([]Option{WithFuncs(template.FuncMap{"upper": strings.ToUpper})}, opts...)...)
+	return s, s.Load(fsys, "cards/*")
+}
+
+func TestRender(t *testing.T) {
+	s, err := loadSet(t, map[string]string{
+		"cards/approval.json": approvalTemplate,
+		"cards/notes.yaml":    "card_type: text_notice",
+		"cards/readme.txt":    "ignored",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := s.Names(); len(names) != 1 || names[0] != "cards/approval" {
+		t.Fatalf("Names = %v, want only the JSON template", names)
+	}
+	card, err := s.Render("cards/approval", approvalData{Title: "报销", Dept: "rd", Amount: 12.5, ID: "task_1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if card.MainTitle.Title != "报销" || card.MainTitle.Desc != "RD" || card.TaskID != "task_1" {
+		t.Errorf("card = %+v", card.MainTitle)
+	}
+	if card.HorizontalContentList[0].Value != "12.50" || card.ButtonList[1].Style != wecomapi.ButtonStyleRed {
+		t.Errorf("card = %+v", card)
+	}
+
+	if _, err := s.Render("cards/missing", nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("missing template = %v, want ErrNotFound", err)
+	}
+}
+
+func TestRenderErrors(t *testing.T) {
+	s, err := loadSet(t, map[string]string{"cards/approval.json": approvalTemplate})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name      string
+		data      any
+		wantField string
+		wantErr   string
+	}{
+		{"missing key", map[string]any{"Title": "t", "Dept": "d", "Amount": 1.0}, "task_id", "ID"},
+		{"invalid task_id", approvalData{Title: "t", ID: "bad id"}, "task_id", "invalid character"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.Render("cards/approval", tt.data)
+			var te *Error
+			if !errors.As(err, &te) {
+				t.Fatalf("Render = %v, want *Error", err)
+			}
+			if te.File != "cards/approval.json" || te.Field != tt.wantField || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Render = %#v (%v), want field %q containing %q", te, err, tt.wantField, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRenderValidationField(t *testing.T) {
+	s, err := loadSet(t, map[string]string{
+		"cards/vote.json": `{"card_type":"vote_interaction","main_title":{"title":"投票"},"task_id":"v",
+			"checkbox":{"question_key":"q","mode":0,"option_list":[{"id":"{{.ID}}","text":"A"}]},
+			"submit_button":{"text":"提交","key":"submit"}}`,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = s.Render("cards/vote", map[string]string{"ID": ""})
+	var te *Error
+	if !errors.As(err, &te) || te.Field != "checkbox.option_list[0].id" {
+		t.Fatalf("Render = %v, want field checkbox.option_list[0].id", err)
+	}
+	var errs wecomapi.ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Errorf("Render error %v does not wrap ValidationErrors", err)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      string
+		wantField string
+	}{
+		{"unknown field", `{"card_type":"text_notice","main_title":{"titel":"x"}}`, "main_title.titel"},
+		{"unknown nested list field", `{"card_type":"button_interaction","button_list":[{"text":"a","kye":"k"}]}`, "button_list[0].kye"},
+		{"bad placeholder", `{"card_type":"text_notice","sub_title_text":"{{.Name"}`, "sub_title_text"},
+		{"wrong type", `{"card_type":"text_notice","main_title":"title"}`, "main_title"},
+		{"invalid json", `{"card_type":`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadSet(t, map[string]string{"cards/bad.json": tt.data})
+			var te *Error
+			if !errors.As(err, &te) {
+				t.Fatalf("Load = %v, want *Error", err)
+			}
+			if te.File != "cards/bad.json" || te.Field != tt.wantField {
+				t.Errorf("Load error = %v (field %q), want field %q", err, te.Field, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestRenderStrict(t *testing.T) {
+	files := map[string]string{"cards/approval.json": approvalTemplate}
+	data := approvalData{Title: strings.Repeat("长", 30), ID: "t"}
+
+	lax, err := loadSet(t, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := lax.Render("cards/approval", data); err != nil {
+		t.Errorf("non-strict Render = %v", err)
+	}
+
+	strict, err := loadSet(t, files, WithStrict())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = strict.Render("cards/approval", data)
+	var te *Error
+	if !errors.As(err, &te) || te.Field != "main_title.title" {
+		t.Errorf("strict Render = %v, want field main_title.title", err)
+	}
+}
+
+func TestWithDecoder(t *testing.T) {
+	var decoded []string
+	dec := func(data []byte, v any) error {
+		decoded = append(decoded, string(data))
+		return decodeJSON([]byte(strings.ReplaceAll(string(data), "'", `"`)), v)
+	}
+	s, err := loadSet(t, map[string]string{
+		"cards/a.yaml": `{'card_type':'text_notice','sub_title_text':'{{.}}','card_action':{'type':1,'url':'https://example.com'}}`,
+	}, WithDecoder(".yaml", dec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	card, err := s.Render("cards/a", "hello")
+	if err != nil || card.SubTitleText != "hello" || len(decoded) != 1 {
+		t.Errorf("Render = %+v, %v (decoded %d)", card, err, len(decoded))
+	}
+}
@@ -0,0 +1,35 @@
+package wecomwelcome
+
+import (
+	"context"
+	"sync"
+)
+
+// Store 记录用户每天是否已发送过欢迎语。
+type Store interface {
+	// MarkWelcomed 原子地记录用户在day（格式为 2006-01-02）已发送欢迎语，
+	// 返回该用户当天是否为首次记录。
+	MarkWelcomed(ctx context.Context, userKey, day string) (first bool, err error)
+}
+
+// MemoryStore 基于内存的欢迎语状态存储，每个用户仅保留最近一次的日期。
+type MemoryStore struct {
+	mu   sync.Mutex
+	days map[string]string
+}
+
+// NewMemoryStore 创建内存欢迎语状态存储。
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{days: make(map[string]string)}
+}
+
+// MarkWelcomed 记录用户当天已发送欢迎语。
+func (m *MemoryStore) MarkWelcomed(_ context.Context, userKey, day string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.days[userKey] == day {
+		return false, nil
+	}
+	m.days[userKey] = day
+	return true, nil
+}
@@ -0,0 +1,131 @@
+// Package wecomwelcome 处理进入会话事件，每个用户每天只回复一次欢迎语。
+package wecomwelcome
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/go-sphere/wecom-bot-api/wecomapi"
+)
+
+// dayLayout 日期格式。
+const dayLayout = "2006-01-02"
+
+// WelcomeFunc 生成欢迎语回复，只能回复文本或模板卡片。
+type WelcomeFunc func(ctx context.Context, cb *wecomapi.Callback) (*wecomapi.PassiveReply, error)
+
+// Text 返回文本欢迎语，内容中的 {userid} 和 {corpid} 会替换为进入会话的用户和企业。
+func Text(content string) WelcomeFunc {
+	return func(_ context.Context, cb *wecomapi.Callback) (*wecomapi.PassiveReply, error) {
+		return wecomapi.NewTextReply(personalise(content, cb)), nil
+	}
+}
+
+// Card 返回模板卡片欢迎语，每次回复使用卡片的副本。
+func Card(card *wecomapi.TemplateCard) WelcomeFunc {
+	return func(context.Context, *wecomapi.Callback) (*wecomapi.PassiveReply, error) {
+		return wecomapi.NewTemplateCardReply(card.Clone()), nil
+	}
+}
+
+func personalise(s string, cb *wecomapi.Callback) string {
+	return strings.NewReplacer("{userid}", cb.From.UserID, "{corpid}", cb.From.CorpID).Replace(s)
+}
+
+// Manager 按用户和企业选择欢迎语，并保证每个用户每天只回复一次。
+type Manager struct {
+	store   Store
+	welcome WelcomeFunc
+	users   map[string]WelcomeFunc
+	corps   map[string]WelcomeFunc
+	loc     *time.Location
+	now     func() time.Time
+}
+
+// Option 配置 Manager。
+type Option func(*Manager)
+
+// WithLocation 设置划分“每天”所用的时区，默认为本地时区。
+func WithLocation(loc *time.Location) Option {
+	return func(m *Manager) {
+		m.loc = loc
+	}
+}
+
+// WithUserWelcome 为指定用户设置欢迎语，优先于企业和默认欢迎语。
+func WithUserWelcome(userID string, fn WelcomeFunc) Option {
+	return func(m *Manager) {
+		m.users[userID] = fn
+	}
+}
+
+// WithCorpWelcome 为指定企业的用户设置欢迎语，优先于默认欢迎语。
+func WithCorpWelcome(corpID string, fn WelcomeFunc) Option {
+	return func(m *Manager) {
+		m.corps[corpID] = fn
+	}
+}
+
+// NewManager 创建欢迎语管理器，welcome 为默认欢迎语。
+func NewManager(store Store, welcome WelcomeFunc, opts ...Option) *Manager {
+	m := &Manager{
+		store:   store,
+		welcome: welcome,
+		users:   make(map[string]WelcomeFunc),
+		corps:   make(map[string]WelcomeFunc),
+		loc:     time.Local,
+		now:     time.Now,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
+}
+
+// HandleEvent 处理进入会话事件：用户当天首次进入时回复欢迎语，再次进入时返回空回复，
+// 可直接注册到 wecomapi.Router。欢迎语生成并通过 wecomapi.CheckReply 检查后才记录状态，
+// 生成或检查失败时不占用当天的欢迎语，下次进入会重试；因此当天再次进入时仍会调用 WelcomeFunc。
+func (m *Manager) HandleEvent(ctx context.Context, cb *wecomapi.Callback) (*wecomapi.PassiveReply, error) {
+	if cb.Event == nil || cb.Event.EventType != wecomapi.EventTypeEnterChat {
+		return nil, errors.New("wecomwelcome: callback is not an enter_chat event")
+	}
+	fn := m.pick(cb.From)
+	if fn == nil {
+		return nil, nil
+	}
+	reply, err := fn(ctx, cb)
+	if err != nil {
+		return nil, err
+	}
+	if err := wecomapi.CheckReply(cb, reply); err != nil {
+		return nil, err
+	}
+	if reply == nil {
+		return nil, nil
+	}
+	first, err := m.store.MarkWelcomed(ctx, userKey(cb.From), m.now().In(m.loc).Format(dayLayout))
+	if err != nil || !first {
+		return nil, err
+	}
+	return reply, nil
+}
+
+func (m *Manager) pick(from wecomapi.From) WelcomeFunc {
+	if fn, ok := m.users[from.UserID]; ok {
+		return fn
+	}
+	if fn, ok := m.corps[from.CorpID]; ok && from.CorpID != "" {
+		return fn
+	}
+	return m.welcome
+}
+
+// userKey 返回用户在存储中的键，区分不同企业的同名用户。
+func userKey(from wecomapi.From) string {
+	if from.CorpID == "" {
+		return from.UserID
+	}
+	return from.CorpID + "/" + from.UserID
+}
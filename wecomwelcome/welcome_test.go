@@ -0,0 +1,135 @@
+package wecomwelcome
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-sphere/wecom-bot-api/wecomapi"
+)
+
+func enterChat(corpID, userID string) *wecomapi.Callback {
+	return &wecomapi.Callback{
+		MsgType: wecomapi.CallbackMsgTypeEvent,
+		From:    wecomapi.From{CorpID: corpID, UserID: userID},
+		Event:   &wecomapi.Event{EventType: wecomapi.EventTypeEnterChat},
+	}
+}
+
+func TestHandleEventOncePerDay(t *testing.T) {
+	calls := 0
+	welcome := func(ctx context.Context, cb *wecomapi.Callback) (*wecomapi.PassiveReply, error) {
+		calls++
+		return Text("你好 {userid}")(ctx, cb)
+	}
+	now := time.Date(2025, 1, 2, 23, 0, 0, 0, time.UTC)
+	m := NewManager(NewMemoryStore(), welcome, WithLocation(time.UTC))
+	m.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	reply, err := m.HandleEvent(ctx, enterChat("c1", "u1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reply == nil || reply.Text == nil || reply.Text.Content != "你好 u1" {
+		t.Fatalf("first reply = %+v", reply)
+	}
+	reply, err = m.HandleEvent(ctx, enterChat("c1", "u1"))
+	if err != nil || reply != nil {
+		t.Fatalf("second reply = %+v, %v; want empty", reply, err)
+	}
+	// 欢迎语先生成再记录状态，再次进入时仍会生成但不回复。
+	if calls != 2 {
+		t.Errorf("welcome built %d times, want 2", calls)
+	}
+
+	// 其他企业的同名用户单独计算。
+	if reply, _ := m.HandleEvent(ctx, enterChat("c2", "u1")); reply == nil {
+		t.Error("same user id in another corp was not welcomed")
+	}
+
+	now = now.Add(2 * time.Hour)
+	if reply, _ := m.HandleEvent(ctx, enterChat("c1", "u1")); reply == nil {
+		t.Error("user was not welcomed on the next day")
+	}
+}
+
+func TestHandleEventFailureKeepsDay(t *testing.T) {
+	fail := errors.New("backend unavailable")
+	tests := []struct {
+		name    string
+		reply   *wecomapi.PassiveReply
+		err     error
+		wantErr error
+	}{
+		{"welcome error", nil, fail, fail},
+		{"illegal reply", wecomapi.NewMarkdownReply("hi"), nil, wecomapi.ErrIllegalReply},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broken := true
+			welcome := func(ctx context.Context, cb *wecomapi.Callback) (*wecomapi.PassiveReply, error) {
+				if broken {
+					return tt.reply, tt.err
+				}
+				return Text("你好")(ctx, cb)
+			}
+			m := NewManager(NewMemoryStore(), welcome)
+			ctx := context.Background()
+			if _, err := m.HandleEvent(ctx, enterChat("", "u1")); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("HandleEvent = %v, want %v", err, tt.wantErr)
+			}
+			broken = false
+			reply, err := m.HandleEvent(ctx, enterChat("", "u1"))
+			if err != nil || reply == nil {
+				t.Fatalf("retry = %+v, %v; want the welcome", reply, err)
+			}
+			if reply, _ := m.HandleEvent(ctx, enterChat("", "u1")); reply != nil {
+				t.Errorf("third entry = %+v, want empty", reply)
+			}
+		})
+	}
+}
+
+func TestHandleEventEmptyWelcome(t *testing.T) {
+	empty := true
+	welcome := func(ctx context.Context, cb *wecomapi.Callback) (*wecomapi.PassiveReply, error) {
+		if empty {
+			return nil, nil
+		}
+		return Text("你好")(ctx, cb)
+	}
+	m := NewManager(NewMemoryStore(), welcome)
+	ctx := context.Background()
+	if reply, err := m.HandleEvent(ctx, enterChat("", "u1")); reply != nil || err != nil {
+		t.Fatalf("empty welcome = %+v, %v", reply, err)
+	}
+	empty = false
+	if reply, _ := m.HandleEvent(ctx, enterChat("", "u1")); reply == nil {
+		t.Error("empty welcome used up the day")
+	}
+}
+
+func TestHandleEventPick(t *testing.T) {
+	m := NewManager(NewMemoryStore(), Text("default"),
+		WithCorpWelcome("c1", Text("corp")),
+		WithUserWelcome("vip", Text("user")),
+	)
+	tests := []struct {
+		corpID, userID, want string
+	}{
+		{"c1", "vip", "user"},
+		{"c1", "u1", "corp"},
+		{"c2", "u2", "default"},
+	}
+	for _, tt := range tests {
+		reply, err := m.HandleEvent(context.Background(), enterChat(tt.corpID, tt.userID))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reply.Text.Content != tt.want {
+			t.Errorf("%s/%s welcome = %q, want %q", tt.corpID, tt.userID, reply.Text.Content, tt.want)
+		}
+	}
+}